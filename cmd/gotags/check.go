@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/gaigals/gotags"
+	"github.com/gaigals/gotags/manifest"
+	"github.com/gaigals/gotags/source"
+)
+
+// problem is single reported tag problem.
+type problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Field   string `json:"field"`
+	Key     string `json:"key,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	manifestPath := flags.String("manifest", manifest.DefaultFilename,
+		"settings manifest file")
+	format := flags.String("format", "text", "output format: text or json")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return exitError
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "gotags: unknown format '%s'\n", *format)
+		return exitError
+	}
+
+	settingsManifest, err := manifest.Load(*manifestPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotags: %v\n", err)
+		return exitError
+	}
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	problems, err := check(settingsManifest.Settings(), patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotags: %v\n", err)
+		return exitError
+	}
+
+	err = report(stdout, *format, problems)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotags: %v\n", err)
+		return exitError
+	}
+
+	if len(problems) > 0 {
+		return exitProblems
+	}
+
+	return exitOK
+}
+
+// check parses Go files of every pattern and returns all tag problems.
+func check(tg *gotags.TagSettings, patterns []string) ([]problem, error) {
+	problems := []problem{}
+
+	for _, pattern := range patterns {
+		dirs, err := expandPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dir := range dirs {
+			_, err = source.ParseDir(tg, dir)
+
+			var errList source.ErrorList
+			if errors.As(err, &errList) {
+				problems = appendProblems(problems, errList)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// expandPattern returns directories matched by pattern, "dir/..." matches
+// dir and all its subdirectories except hidden, testdata and vendor ones.
+func expandPattern(pattern string) ([]string, error) {
+	root, recursive := strings.CutSuffix(pattern, "...")
+	if !recursive {
+		return []string{pattern}, nil
+	}
+
+	root = filepath.Clean(strings.TrimSuffix(root, "/"))
+	if root == "" {
+		root = "."
+	}
+
+	var dirs []string
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		name := entry.Name()
+		if path != root &&
+			(strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+
+		dirs = append(dirs, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dirs, nil
+}
+
+func appendProblems(problems []problem, errList source.ErrorList) []problem {
+	for _, err := range errList {
+		reported := problem{
+			File:    err.Pos.Filename,
+			Line:    err.Pos.Line,
+			Column:  err.Pos.Column,
+			Message: err.Err.Error(),
+		}
+
+		var parseErr *gotags.ParseError
+		if errors.As(err, &parseErr) {
+			reported.Field = parseErr.Field
+			reported.Key = parseErr.Key
+			if parseErr.Kind != nil {
+				reported.Kind = parseErr.Kind.Error()
+			}
+		}
+
+		problems = append(problems, reported)
+	}
+
+	return problems
+}
+
+func report(output io.Writer, format string, problems []problem) error {
+	if format == "json" {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(problems)
+	}
+
+	for _, v := range problems {
+		_, err := fmt.Fprintf(output, "%s:%d:%d: %s\n",
+			v.File, v.Line, v.Column, v.Message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gaigals/gotags"
+	"github.com/gaigals/gotags/manifest"
+	"github.com/gaigals/gotags/source"
+)
+
+const gotagsImport = "github.com/gaigals/gotags"
+
+// generator collects data of generated file.
+type generator struct {
+	tg      *gotags.TagSettings
+	fileSet *token.FileSet
+	files   []*ast.File
+	imports map[string]string // Import path => import spec source.
+	buf     bytes.Buffer
+}
+
+// generatedField is tagged struct field of generated struct type.
+type generatedField struct {
+	name     string
+	typeExpr string
+	tags     []gotags.Tag
+}
+
+func runGenerate(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	typeNames := flags.String("type", "", "comma-separated list of struct type names")
+	manifestPath := flags.String("manifest", "",
+		"settings manifest file (default "+manifest.DefaultFilename+
+			" in package or parent directory)")
+	output := flags.String("output", "", "output file (default <type>_gotags.go)")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return exitError
+	}
+
+	if *typeNames == "" {
+		fmt.Fprintln(stderr, "gotags: -type is required")
+		return exitError
+	}
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	if *manifestPath == "" {
+		*manifestPath = findManifest(dir)
+	}
+
+	settingsManifest, err := manifest.Load(*manifestPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotags: %v\n", err)
+		return exitError
+	}
+
+	types := strings.Split(*typeNames, ",")
+
+	src, err := generate(
+		settingsManifest.Settings(),
+		dir,
+		types,
+		"gotags generate "+strings.Join(args, " "),
+	)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotags: %v\n", err)
+		return exitError
+	}
+
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(types[0])+"_gotags.go")
+	}
+
+	err = os.WriteFile(*output, src, 0o644)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotags: %v\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// findManifest returns manifest file from dir or closest parent directory,
+// stopping at module root. Returns DefaultFilename if none is found.
+func findManifest(dir string) string {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return manifest.DefaultFilename
+	}
+
+	for {
+		filename := filepath.Join(current, manifest.DefaultFilename)
+		if _, err := os.Stat(filename); err == nil {
+			return filename
+		}
+
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return manifest.DefaultFilename
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return manifest.DefaultFilename
+		}
+
+		current = parent
+	}
+}
+
+// generate returns formatted Go source with accessors of typeNames declared
+// in package dir. Tags are validated first, any tag problem fails generation.
+func generate(
+	tg *gotags.TagSettings,
+	dir string,
+	typeNames []string,
+	command string,
+) ([]byte, error) {
+	_, err := source.ParseDir(tg, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := &generator{
+		tg:      tg,
+		fileSet: token.NewFileSet(),
+		imports: map[string]string{},
+	}
+
+	err = gen.parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+
+	for _, typeName := range typeNames {
+		err = gen.generateType(&body, strings.TrimSpace(typeName))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gen.printf("// Code generated by \"%s\"; DO NOT EDIT.\n\n", command)
+	gen.printf("package %s\n\n", gen.files[0].Name.Name)
+	gen.printImports()
+	gen.buf.Write(body.Bytes())
+
+	src, err := format.Source(gen.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+func (gen *generator) parseDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(
+			gen.fileSet,
+			filepath.Join(dir, name),
+			nil,
+			parser.SkipObjectResolution,
+		)
+		if err != nil {
+			return err
+		}
+
+		gen.files = append(gen.files, file)
+	}
+
+	if len(gen.files) == 0 {
+		return fmt.Errorf("no Go files in '%s'", dir)
+	}
+
+	return nil
+}
+
+func (gen *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&gen.buf, format, args...)
+}
+
+func (gen *generator) printImports() {
+	paths := make([]string, 0, len(gen.imports))
+	for importPath := range gen.imports {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+
+	gen.printf("import (\n")
+	for _, importPath := range paths {
+		gen.printf("\t%s\n", gen.imports[importPath])
+	}
+	if len(paths) > 0 {
+		gen.printf("\n")
+	}
+	gen.printf("\t%q\n)\n\n", gotagsImport)
+}
+
+// lookupType returns struct type typeName and file it is declared in.
+func (gen *generator) lookupType(typeName string) (*ast.StructType, *ast.File, error) {
+	for _, file := range gen.files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Name.Name != typeName {
+					continue
+				}
+
+				if typeSpec.TypeParams != nil {
+					return nil, nil, fmt.Errorf("type '%s': generic types are not supported",
+						typeName)
+				}
+
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					return nil, nil, fmt.Errorf("type '%s' is not a struct", typeName)
+				}
+
+				return structType, file, nil
+			}
+		}
+	}
+
+	return nil, nil, fmt.Errorf("type '%s' not found", typeName)
+}
+
+func (gen *generator) generateType(output io.Writer, typeName string) error {
+	structType, file, err := gen.lookupType(typeName)
+	if err != nil {
+		return err
+	}
+
+	fields, err := gen.taggedFields(structType, file)
+	if err != nil {
+		return fmt.Errorf("type '%s': %w", typeName, err)
+	}
+
+	namespace := exportedName(gen.tg.Name)
+	fieldsType := typeName + namespace + "Fields"
+	receiver := strings.ToLower(typeName[:1])
+
+	if len(fields) > 0 {
+		fmt.Fprintf(output, "var (\n")
+		for _, field := range fields {
+			fmt.Fprintf(output, "\t%s = %s\n",
+				tagsVarName(typeName, gen.tg.Name, field.name), tagsLiteral(field.tags))
+		}
+		fmt.Fprintf(output, ")\n\n")
+	}
+
+	fmt.Fprintf(output, "// %s holds fields of %s tagged with %q.\n",
+		fieldsType, typeName, gen.tg.Name)
+	fmt.Fprintf(output, "type %s struct {\n", fieldsType)
+	for _, field := range fields {
+		fmt.Fprintf(output, "\t%s gotags.StaticField[%s]\n", field.name, field.typeExpr)
+	}
+	fmt.Fprintf(output, "}\n\n")
+
+	fmt.Fprintf(output, "// %sFields returns fields of %s tagged with %q.\n",
+		namespace, typeName, gen.tg.Name)
+	fmt.Fprintf(output, "func (%s *%s) %sFields() %s {\n",
+		receiver, typeName, namespace, fieldsType)
+	fmt.Fprintf(output, "\treturn %s{\n", fieldsType)
+	for _, field := range fields {
+		fmt.Fprintf(output, "\t\t%s: gotags.NewStaticField(%q, &%s.%s, %s),\n",
+			field.name, field.name, receiver, field.name,
+			tagsVarName(typeName, gen.tg.Name, field.name))
+	}
+	fmt.Fprintf(output, "\t}\n}\n\n")
+
+	fmt.Fprintf(output, "// Fields returns fields in the same order and with the same tags as\n")
+	fmt.Fprintf(output, "// TagSettings.ParseStruct.\n")
+	fmt.Fprintf(output, "func (fields %s) Fields() []gotags.Field {\n", fieldsType)
+	fmt.Fprintf(output, "\treturn []gotags.Field{\n")
+	for _, field := range fields {
+		fmt.Fprintf(output, "\t\tfields.%s.Field(),\n", field.name)
+	}
+	fmt.Fprintf(output, "\t}\n}\n\n")
+
+	return nil
+}
+
+// taggedFields returns exported fields of structType tagged with settings
+// tag name, like TagSettings.ParseStruct without recursive parsing.
+func (gen *generator) taggedFields(
+	structType *ast.StructType,
+	file *ast.File,
+) ([]generatedField, error) {
+	var fields []generatedField
+
+	for _, astField := range structType.Fields.List {
+		// Unexported fields are skipped before their tag is parsed, like
+		// ParseStruct does.
+		names := exportedNames(fieldNames(astField))
+		if astField.Tag == nil || len(names) == 0 {
+			continue
+		}
+
+		rawTag, err := strconv.Unquote(astField.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err := gen.tg.ParseStructTag(reflect.StructTag(rawTag))
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) == 0 {
+			continue
+		}
+
+		typeExpr, err := gen.typeExpr(astField.Type, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			if name == "Fields" {
+				return nil, errors.New("field name 'Fields' conflicts with generated method")
+			}
+
+			fields = append(fields, generatedField{
+				name:     name,
+				typeExpr: typeExpr,
+				tags:     tags,
+			})
+		}
+	}
+
+	return fields, nil
+}
+
+// exportedNames returns exported names of names.
+func exportedNames(names []string) []string {
+	var exported []string
+
+	for _, name := range names {
+		if ast.IsExported(name) {
+			exported = append(exported, name)
+		}
+	}
+
+	return exported
+}
+
+// typeExpr returns source of field type expression and records imports
+// the expression uses.
+func (gen *generator) typeExpr(expr ast.Expr, file *ast.File) (string, error) {
+	var buf bytes.Buffer
+
+	err := printer.Fprint(&buf, gen.fileSet, expr)
+	if err != nil {
+		return "", err
+	}
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		ident, ok := selector.X.(*ast.Ident)
+		if ok {
+			gen.addImport(file, ident.Name)
+		}
+
+		return false
+	})
+
+	return buf.String(), nil
+}
+
+// addImport records import of file with package name.
+func (gen *generator) addImport(file *ast.File, name string) {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		importName := path.Base(importPath)
+		importSource := spec.Path.Value
+
+		if spec.Name != nil {
+			importName = spec.Name.Name
+			importSource = spec.Name.Name + " " + spec.Path.Value
+		}
+
+		if importName == name {
+			gen.imports[importPath] = importSource
+			return
+		}
+	}
+}
+
+// fieldNames returns names of astField, embedded field is named by its type.
+func fieldNames(astField *ast.Field) []string {
+	if len(astField.Names) > 0 {
+		names := make([]string, len(astField.Names))
+		for idx, ident := range astField.Names {
+			names[idx] = ident.Name
+		}
+
+		return names
+	}
+
+	expr := astField.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	switch typeExpr := expr.(type) {
+	case *ast.Ident:
+		return []string{typeExpr.Name}
+	case *ast.SelectorExpr:
+		return []string{typeExpr.Sel.Name}
+	default:
+		return nil
+	}
+}
+
+// exportedName converts tag name to exported identifier, like
+// "json-schema" => "JsonSchema".
+func exportedName(name string) string {
+	var builder strings.Builder
+	upper := true
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+func tagsVarName(typeName, tagName, fieldName string) string {
+	return "_" + typeName + "_" + exportedName(tagName) + "_" + fieldName
+}
+
+func tagsLiteral(tags []gotags.Tag) string {
+	parts := make([]string, len(tags))
+
+	for idx, tag := range tags {
+		if tag.Value == "" {
+			parts[idx] = fmt.Sprintf("{Key: %q}", tag.Key)
+			continue
+		}
+
+		parts[idx] = fmt.Sprintf("{Key: %q, Value: %q}", tag.Key, tag.Value)
+	}
+
+	return "[]gotags.Tag{" + strings.Join(parts, ", ") + "}"
+}
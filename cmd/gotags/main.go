@@ -0,0 +1,63 @@
+// Command gotags works with struct tags defined by gotags settings.
+//
+// Usage:
+//
+//	gotags check [-manifest gotags.json] [-format text|json] [packages]
+//
+// check scans Go source files for struct tags with the manifest tag name and
+// reports every unknown key, missing argument and missing required key with
+// its file position. Packages are directories, "dir/..." scans dir
+// recursively, default is "./...". Exit status is 1 if problems were found
+// and 2 on usage or I/O errors.
+//
+//	gotags generate -type User[,Order] [-manifest gotags.json] [-output file] [dir]
+//
+// generate writes Go code with pre-parsed tags and typed field accessors for
+// passed struct types, so fields can be read without tag parsing or
+// reflection. It is meant to be used with go:generate:
+//
+//	//go:generate gotags generate -type User
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	exitOK       = 0
+	exitProblems = 1
+	exitError    = 2
+)
+
+const usage = `usage: gotags <command> [arguments]
+
+commands:
+  check       report invalid struct tags
+  generate    generate reflection-free field accessors
+`
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:], stdout, stderr)
+	case "generate":
+		return runGenerate(args[1:], stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "gotags: unknown command '%s'\n%s", args[0], usage)
+		return exitError
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
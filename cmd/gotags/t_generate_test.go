@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+// testGenerateModels uses ~ instead of backquote.
+var testGenerateModels = strings.ReplaceAll(`package models
+
+import "time"
+
+type User struct {
+	Name    string        ~validator:"required" json:"name"~
+	Timeout time.Duration ~validator:"gt:1s"~
+	Note    string
+	private string ~validator:"required"~
+	hidden  string ~validator:"bogus"~
+}
+`, "~", "`")
+
+// testGenerateCheck compares generated Fields() with ParseStruct result,
+// uses ~ instead of backquote.
+var testGenerateCheck = strings.ReplaceAll(`package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"example.com/app/models"
+	"github.com/gaigals/gotags/manifest"
+)
+
+func main() {
+	settingsManifest, err := manifest.Load("gotags.json")
+	if err != nil {
+		fail(err)
+	}
+
+	user := &models.User{Name: "John", Timeout: time.Minute}
+
+	fields, err := settingsManifest.Settings().ParseStruct(user)
+	if err != nil {
+		fail(err)
+	}
+
+	static := user.ValidatorFields().Fields()
+	if len(static) != len(fields) {
+		fail(fmt.Errorf("got %d fields, expected %d", len(static), len(fields)))
+	}
+
+	for idx, field := range fields {
+		if static[idx].Name != field.Name ||
+			static[idx].Path != field.Path ||
+			static[idx].Kind != field.Kind ||
+			!reflect.DeepEqual(static[idx].Tags, field.Tags) ||
+			static[idx].Value.Addr().Pointer() != field.Value.Addr().Pointer() {
+			fail(fmt.Errorf("field %d: got %+v, expected %+v", idx, static[idx], field))
+		}
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+`, "~", "`")
+
+func Test_Generate(t *testing.T) {
+	dir := writeTestModule(t, testGenerateModels)
+	modelsDir := filepath.Join(dir, "models")
+
+	var stderr bytes.Buffer
+	code := run([]string{"generate", "-type", "User", modelsDir}, nil, &stderr)
+	testza.AssertEqual(t, exitOK, code, stderr.String())
+
+	src, err := os.ReadFile(filepath.Join(modelsDir, "user_gotags.go"))
+	testza.AssertNoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "", src, 0)
+	testza.AssertNoError(t, err, "generated code must be valid Go")
+
+	for _, expected := range []string{
+		`_User_Validator_Name    = []gotags.Tag{{Key: "required"}}`,
+		`_User_Validator_Timeout = []gotags.Tag{{Key: "gt", Value: "1s"}}`,
+		`Timeout gotags.StaticField[time.Duration]`,
+		`func (u *User) ValidatorFields() UserValidatorFields {`,
+		`Name:    gotags.NewStaticField("Name", &u.Name, _User_Validator_Name),`,
+		`func (fields UserValidatorFields) Fields() []gotags.Field {`,
+		`"time"`,
+	} {
+		testza.AssertContains(t, string(src), expected)
+	}
+
+	testza.AssertNotContains(t, string(src), "Note")
+	testza.AssertNotContains(t, string(src), "private")
+
+	t.Run("Generated code matches ParseStruct", func(t *testing.T) {
+		goBinary, err := exec.LookPath("go")
+		if err != nil {
+			t.Skip("go binary not found")
+		}
+
+		writeGoModule(t, dir)
+
+		err = os.MkdirAll(filepath.Join(dir, "check"), os.ModePerm)
+		testza.AssertNoError(t, err)
+
+		err = os.WriteFile(filepath.Join(dir, "check", "main.go"),
+			[]byte(testGenerateCheck), 0o600)
+		testza.AssertNoError(t, err)
+
+		cmd := exec.Command(goBinary, "run", "./check")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOPROXY=off")
+
+		output, err := cmd.CombinedOutput()
+		testza.AssertNoError(t, err, string(output))
+	})
+}
+
+// writeGoModule makes dir a module which uses gotags of this repository.
+func writeGoModule(t *testing.T, dir string) {
+	t.Helper()
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	testza.AssertNoError(t, err)
+
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	testza.AssertNoError(t, err)
+
+	goMod := "module example.com/app\n\ngo 1.22\n\n" +
+		"require github.com/gaigals/gotags v0.0.0\n\n" +
+		"replace github.com/gaigals/gotags => " + root + "\n"
+
+	err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o600)
+	testza.AssertNoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0o600)
+	testza.AssertNoError(t, err)
+}
+
+func Test_GenerateErrors(t *testing.T) {
+	dir := writeTestModule(t, testModels)
+	modelsDir := filepath.Join(dir, "models")
+
+	var stderr bytes.Buffer
+
+	code := run([]string{"generate", "-type", "User", modelsDir}, nil, &stderr)
+	testza.AssertEqual(t, exitError, code)
+	testza.AssertContains(t, stderr.String(), "tag 'lt' does not exist")
+
+	stderr.Reset()
+	code = run([]string{"generate", "-type", "Missing", filepath.Join(modelsDir, "nested")},
+		nil, &stderr)
+	testza.AssertEqual(t, exitError, code)
+	testza.AssertContains(t, stderr.String(), "type 'Missing' not found")
+
+	code = run([]string{"generate", modelsDir}, nil, &stderr)
+	testza.AssertEqual(t, exitError, code)
+}
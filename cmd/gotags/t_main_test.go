@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+const testManifest = `{
+	"tag": "validator",
+	"keys": [
+		{"name": "required", "bool": true},
+		{"name": "gt"}
+	]
+}`
+
+// testModels uses ~ instead of backquote.
+var testModels = strings.ReplaceAll(`package models
+
+type User struct {
+	Name string ~validator:"required"~
+	Age  int    ~validator:"gt:10;lt:130"~
+}
+`, "~", "`")
+
+var testValidModels = strings.ReplaceAll(`package nested
+
+type Server struct {
+	Host string ~validator:"required"~
+}
+`, "~", "`")
+
+func writeTestModule(t *testing.T, models string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"gotags.json":                 testManifest,
+		"models/models.go":            models,
+		"models/nested/nested.go":     testValidModels,
+		"models/testdata/broken.go":   models,
+		"models/.hidden/broken.go":    models,
+		"models/models_test.go":       models,
+		"models/nested/nested_doc.md": "",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		testza.AssertNoError(t, err)
+
+		err = os.WriteFile(path, []byte(content), 0o600)
+		testza.AssertNoError(t, err)
+	}
+
+	return dir
+}
+
+func Test_CheckText(t *testing.T) {
+	dir := writeTestModule(t, testModels)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{
+		"check",
+		"-manifest", filepath.Join(dir, "gotags.json"),
+		filepath.Join(dir, "..."),
+	}, &stdout, &stderr)
+
+	testza.AssertEqual(t, exitProblems, code)
+	testza.AssertEqual(t, "", stderr.String())
+	testza.AssertEqual(t,
+		filepath.Join(dir, "models", "models.go")+
+			":5:32: field 'Age': tag 'lt' does not exist\n",
+		stdout.String(),
+	)
+}
+
+func Test_CheckJSON(t *testing.T) {
+	dir := writeTestModule(t, testModels)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{
+		"check",
+		"-manifest", filepath.Join(dir, "gotags.json"),
+		"-format", "json",
+		filepath.Join(dir, "models"),
+	}, &stdout, &stderr)
+
+	testza.AssertEqual(t, exitProblems, code)
+
+	var problems []problem
+	err := json.Unmarshal(stdout.Bytes(), &problems)
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, problems, 1)
+	testza.AssertEqual(t, "Age", problems[0].Field)
+	testza.AssertEqual(t, "lt", problems[0].Key)
+	testza.AssertEqual(t, 5, problems[0].Line)
+	testza.AssertEqual(t, 32, problems[0].Column)
+}
+
+func Test_CheckClean(t *testing.T) {
+	dir := writeTestModule(t, testValidModels)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{
+		"check",
+		"-manifest", filepath.Join(dir, "gotags.json"),
+		filepath.Join(dir, "..."),
+	}, &stdout, &stderr)
+
+	testza.AssertEqual(t, exitOK, code)
+	testza.AssertEqual(t, "", stdout.String())
+}
+
+func Test_CheckErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	testza.AssertEqual(t, exitError, run(nil, &stdout, &stderr))
+	testza.AssertEqual(t, exitError, run([]string{"lint"}, &stdout, &stderr))
+	testza.AssertEqual(t, exitError, run([]string{
+		"check", "-manifest", filepath.Join(t.TempDir(), "missing.json"),
+	}, &stdout, &stderr))
+	testza.AssertEqual(t, exitError, run([]string{
+		"check", "-format", "xml",
+	}, &stdout, &stderr))
+}
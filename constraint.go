@@ -0,0 +1,197 @@
+package gotags
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type constraintKind int
+
+const (
+	constraintRequires constraintKind = iota
+	constraintExcludes
+	constraintExactlyOneOf
+)
+
+// Constraint is a rule between keys of a single field tag, see
+// TagSettings.WithConstraints.
+type Constraint struct {
+	kind constraintKind
+	key  string   // Key which requires other keys, only for Requires.
+	keys []string // Constrained keys.
+	when []string // Constraint is checked only for fields using any of them.
+}
+
+// Requires creates constraint: when key is used, all required keys must be
+// used as well, like `lt` requires `gt`.
+func Requires(key string, required ...string) Constraint {
+	return Constraint{
+		kind: constraintRequires,
+		key:  key,
+		keys: required,
+	}
+}
+
+// Excludes creates constraint: passed keys are mutually exclusive, at most
+// one of them can be used, like `gt` and `eq`.
+func Excludes(keys ...string) Constraint {
+	return Constraint{
+		kind: constraintExcludes,
+		keys: keys,
+	}
+}
+
+// ExactlyOneOf creates constraint: exactly one of passed keys must be used,
+// like one of `email`, `phone` or `url`. It is checked only for fields using
+// any of keys or any key passed to When, so other fields are not affected.
+// Without When it only forbids using several of keys.
+func ExactlyOneOf(keys ...string) Constraint {
+	return Constraint{
+		kind: constraintExactlyOneOf,
+		keys: keys,
+	}
+}
+
+// When returns copy of constraint which is checked only for fields using any
+// of passed keys, like `contact` for ExactlyOneOf("email", "phone", "url").
+func (constraint Constraint) When(keys ...string) Constraint {
+	constraint.when = append(slices.Clone(constraint.when), keys...)
+	return constraint
+}
+
+// WithConstraints adds constraints between keys. Constraints are checked for
+// every parsed field, violations are returned as ParseError of
+// ErrConstraintViolated kind.
+func (tg *TagSettings) WithConstraints(constraints ...Constraint) *TagSettings {
+	tg.constraints = append(tg.constraints, constraints...)
+	tg.resetPlans()
+	return tg
+}
+
+// check returns error if tags violate constraint.
+func (constraint *Constraint) check(tags []Tag) *ParseError {
+	if !constraint.applies(tags) {
+		return nil
+	}
+
+	switch constraint.kind {
+	case constraintRequires:
+		index := tagIndex(tags, constraint.key)
+		if index < 0 {
+			return nil
+		}
+
+		for _, required := range constraint.keys {
+			if tagIndex(tags, required) >= 0 {
+				continue
+			}
+
+			return constraintError(index, constraint.key,
+				"tag key '%s' requires '%s'", constraint.key, required)
+		}
+	case constraintExcludes, constraintExactlyOneOf:
+		var found []string
+		secondIndex := -1
+
+		for _, key := range constraint.keys {
+			index := tagIndex(tags, key)
+			if index < 0 {
+				continue
+			}
+
+			found = append(found, key)
+			if len(found) == 2 {
+				secondIndex = index
+			}
+		}
+
+		if len(found) > 1 && constraint.kind == constraintExcludes {
+			return constraintError(secondIndex, found[1],
+				"tag keys %s are mutually exclusive", quoteKeys(found))
+		}
+		if len(found) > 1 {
+			return constraintError(secondIndex, found[1],
+				"exactly one of tag keys %s is allowed, found %s",
+				quoteKeys(constraint.keys), quoteKeys(found))
+		}
+		if len(found) == 0 && constraint.kind == constraintExactlyOneOf {
+			return constraintError(-1, "",
+				"exactly one of tag keys %s is required", quoteKeys(constraint.keys))
+		}
+	}
+
+	return nil
+}
+
+// applies reports whether constraint must be checked for tags of a field.
+func (constraint *Constraint) applies(tags []Tag) bool {
+	if len(constraint.when) == 0 && constraint.kind != constraintExactlyOneOf {
+		return true
+	}
+
+	if hasAnyKey(tags, constraint.when) {
+		return true
+	}
+
+	return constraint.kind == constraintExactlyOneOf && hasAnyKey(tags, constraint.keys)
+}
+
+// checkConstraints returns error for every violated constraint.
+func (tg *TagSettings) checkConstraints(
+	typeOf reflect.Type,
+	raw string,
+	tags []Tag,
+) []*ParseError {
+	var parseErrs []*ParseError
+
+	for idx := range tg.constraints {
+		parseErr := tg.constraints[idx].check(tags)
+		if parseErr == nil {
+			continue
+		}
+
+		parseErrs = append(parseErrs, tg.locateParseError(parseErr, typeOf, raw))
+	}
+
+	return parseErrs
+}
+
+func constraintError(index int, key, format string, args ...any) *ParseError {
+	parseErr := newParseError(ErrConstraintViolated, key, fmt.Errorf(format, args...))
+	parseErr.tagIndex = index
+	return parseErr
+}
+
+// tagIndex returns index of first tag with passed key, -1 if there is none.
+func tagIndex(tags []Tag, key string) int {
+	for idx, tag := range tags {
+		if tag.Key == key {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// hasAnyKey reports whether tags contain any of keys.
+func hasAnyKey(tags []Tag, keys []string) bool {
+	for _, key := range keys {
+		if tagIndex(tags, key) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteKeys formats keys like `'gt', 'eq'`.
+func quoteKeys(keys []string) string {
+	quoted := make([]string, len(keys))
+	for idx, key := range keys {
+		quoted[idx] = "'" + key + "'"
+	}
+
+	return strings.Join(quoted, ", ")
+}
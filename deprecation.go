@@ -0,0 +1,29 @@
+package gotags
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Deprecation describes use of deprecated key name, see Key.WithDeprecated.
+type Deprecation struct {
+	Type  reflect.Type // Struct type, nil for ParseStructTag.
+	Field string       // Struct field name, empty for ParseStructTag.
+	Tag   string       // Tag name.
+	Name  string       // Deprecated key name found in tag.
+	Key   string       // Canonical key name.
+}
+
+// DeprecationHandler gets called for every deprecated key name use.
+type DeprecationHandler func(deprecation Deprecation)
+
+func (deprecation Deprecation) String() string {
+	msg := fmt.Sprintf("tag key '%s' is deprecated, use '%s'",
+		deprecation.Name, deprecation.Key)
+
+	if deprecation.Field == "" {
+		return msg
+	}
+
+	return fmt.Sprintf("field '%s': %s", deprecation.Field, msg)
+}
@@ -0,0 +1,135 @@
+package gotags
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Error kinds of ParseError, can be checked with errors.Is.
+var (
+	ErrUnknownKey         = errors.New("unknown key")
+	ErrMissingArgument    = errors.New("missing argument")
+	ErrUnexpectedArgument = errors.New("unexpected argument")
+	ErrInvalidValue       = errors.New("invalid value")
+	ErrRequiredKeyMissing = errors.New("required key missing")
+	ErrTrailingEscape     = errors.New("trailing naked backslash")
+	ErrEmptyTag           = errors.New("no keys defined")
+	ErrProcessorFailed    = errors.New("processor failed")
+	ErrConstraintViolated = errors.New("key constraint violated")
+	ErrDuplicateKey       = errors.New("duplicate key")
+	ErrFieldTypeMismatch  = errors.New("key not applicable to field type")
+	ErrInvalidFieldRef    = errors.New("invalid field reference")
+	ErrUnterminatedQuote  = errors.New("unterminated quote")
+)
+
+// ParseError describes tag problem found while parsing struct.
+// Use errors.As to inspect it and errors.Is to check its Kind.
+type ParseError struct {
+	Type   reflect.Type // Struct type containing the field.
+	Field  string       // Full field path, like "Server.TLS.CertFile".
+	Tag    string       // Tag name (namespace), like "validator".
+	Key    string       // Tag key, empty if problem is not key related.
+	Raw    string       // Raw tag string, like "gt:10;lt:130".
+	Offset int          // Byte offset of the problem in Raw, -1 if unknown.
+	Kind   error        // One of Err* kinds.
+	Err    error        // Underlying error.
+
+	tagIndex int // Index of tag in Raw, -1 if not tag related.
+}
+
+func newParseError(kind error, key string, err error) *ParseError {
+	return &ParseError{
+		Key:      key,
+		Offset:   -1,
+		Kind:     kind,
+		Err:      err,
+		tagIndex: -1,
+	}
+}
+
+// Error returns error message prefixed with field path.
+func (err *ParseError) Error() string {
+	message := err.Kind.Error()
+	if err.Err != nil {
+		message = err.Err.Error()
+	}
+
+	if err.Field == "" {
+		return message
+	}
+
+	return fmt.Sprintf("field '%s': %s", err.Field, message)
+}
+
+// Unwrap returns error kind and underlying error.
+func (err *ParseError) Unwrap() []error {
+	if err.Err == nil {
+		return []error{err.Kind}
+	}
+
+	return []error{err.Kind, err.Err}
+}
+
+// newFieldError creates error of already parsed field.
+func newFieldError(kind error, field Field, err error) *ParseError {
+	parseErr := newParseError(kind, "", err)
+	parseErr.Field = field.Path
+	return parseErr
+}
+
+func newStructError(kind error, typeOf reflect.Type, err error) *ParseError {
+	parseErr := newParseError(kind, "", err)
+	parseErr.Type = typeOf
+	return parseErr
+}
+
+// withKey sets key of parseErr and returns it.
+func (err *ParseError) withKey(key string) *ParseError {
+	err.Key = key
+	return err
+}
+
+// withField returns copy of err with field path set.
+func (err *ParseError) withField(path string) *ParseError {
+	errCopy := *err
+	errCopy.Field = path
+	return &errCopy
+}
+
+// asParseError converts tag parsing error to ParseError.
+func asParseError(err error) *ParseError {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr
+	}
+
+	kind := ErrInvalidValue
+	switch {
+	case errors.Is(err, ErrTrailingEscape):
+		kind = ErrTrailingEscape
+	case errors.Is(err, ErrEmptyTag):
+		kind = ErrEmptyTag
+	case errors.Is(err, ErrUnterminatedQuote):
+		kind = ErrUnterminatedQuote
+	}
+
+	return newParseError(kind, "", err)
+}
+
+// joinParseErrors joins errs with errors.Join.
+func joinParseErrors(errs []*ParseError) error {
+	joined := make([]error, len(errs))
+	for idx, err := range errs {
+		joined[idx] = err
+	}
+
+	return errors.Join(joined...)
+}
+
+// withTagIndex marks err as problem of tag at index.
+func withTagIndex(err error, index int) error {
+	parseErr := asParseError(err)
+	parseErr.tagIndex = index
+	return parseErr
+}
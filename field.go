@@ -9,8 +9,13 @@ import (
 type Field struct {
 	Value reflect.Value // Pointer to field
 	Name  string        // Field name
+	Path  string        // Full dotted path from parsed struct, like "Server.TLS"
 	Kind  reflect.Kind  // Field type/kind
 	Tags  []Tag         // Field tag data
+
+	parent     reflect.Value // Struct containing the field, see RefField.
+	parentPath string        // Dotted path of parent followed by dot.
+	settings   *TagSettings  // Settings field was parsed with.
 }
 
 // KeyValueBool acquires tag key value.
@@ -25,6 +30,32 @@ func (field Field) KeyValueBool(key string) (value string, ok bool) {
 	return "", false
 }
 
+// TagByKey returns first tag with passed key.
+// Returns ok(true) if key exists.
+func (field Field) TagByKey(key string) (tag Tag, ok bool) {
+	for _, tag := range field.Tags {
+		if tag.Key == key {
+			return tag, true
+		}
+	}
+
+	return Tag{}, false
+}
+
+// KeyValues returns values of every tag with passed key, in tag order.
+// Useful for keys which are meant to repeat, see DuplicatesAccumulate.
+func (field Field) KeyValues(key string) []string {
+	var values []string
+
+	for _, tag := range field.Tags {
+		if tag.Key == key {
+			values = append(values, tag.Value)
+		}
+	}
+
+	return values
+}
+
 // KeyValue returns tag key value.
 func (field Field) KeyValue(key string) string {
 	value, _ := field.KeyValueBool(key)
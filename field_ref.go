@@ -0,0 +1,180 @@
+package gotags
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// fieldRef is converted value of ValueFieldRef key.
+type fieldRef struct {
+	path string // Dotted field path, like "Server.Port".
+	rest string // Value part after Key.RefSeparator.
+}
+
+func (key *Key) convertFieldRef(value string, escapeCharacter byte) (any, error) {
+	ref := fieldRef{path: value}
+
+	if key.RefSeparator != "" {
+		parts, err := SplitFirstWithEscape(value, key.RefSeparator, escapeCharacter)
+		if err != nil {
+			return nil, err
+		}
+
+		ref.path = parts[0]
+		if len(parts) > 1 {
+			ref.rest = parts[1]
+		}
+	}
+
+	if ref.path == "" {
+		return nil, key.valueTypeError(value)
+	}
+
+	return ref, nil
+}
+
+// checkFieldRefs returns error for every field reference in tags which does
+// not exist in struct typeOf.
+func (tg *TagSettings) checkFieldRefs(typeOf reflect.Type, tags []Tag) []error {
+	var errs []error
+
+	for idx, tag := range tags {
+		ref, ok := tag.converted().(fieldRef)
+		if !ok {
+			continue
+		}
+
+		if _, ok := lookupFieldPath(typeOf, ref.path); ok {
+			continue
+		}
+
+		errs = append(errs, withTagIndex(newParseError(ErrInvalidFieldRef, tag.Key,
+			fmt.Errorf("tag '%s' references unknown field '%s'", tag.Key, ref.path)), idx))
+	}
+
+	return errs
+}
+
+// lookupFieldPath resolves dotted field path in struct typeOf, pointers to
+// structs are dereferenced. Promoted fields of embedded structs are found as
+// well.
+func lookupFieldPath(typeOf reflect.Type, path string) (reflect.StructField, bool) {
+	var structField reflect.StructField
+
+	for _, name := range strings.Split(path, ".") {
+		for typeOf.Kind() == reflect.Ptr {
+			typeOf = typeOf.Elem()
+		}
+		if typeOf.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+
+		var ok bool
+		structField, ok = typeOf.FieldByName(name)
+		if !ok || !structField.IsExported() {
+			return reflect.StructField{}, false
+		}
+
+		typeOf = structField.Type
+	}
+
+	return structField, true
+}
+
+// RefField returns field referenced by value of key tag, see
+// Key.WithFieldRef. Path is resolved from the struct containing field.
+// Returned field has tags of the same TagSettings, if it is tagged.
+func (field Field) RefField(key string) (Field, error) {
+	tag, ok := field.TagByKey(key)
+	if !ok {
+		return Field{}, fmt.Errorf("%s: tag '%s' not found", field.Path, key)
+	}
+
+	ref, ok := tag.converted().(fieldRef)
+	if !ok {
+		return Field{}, fmt.Errorf("%s: tag '%s' is not a field reference",
+			field.Path, key)
+	}
+
+	if !field.parent.IsValid() {
+		return Field{}, errors.New(field.Path + ": field has no parent struct")
+	}
+
+	parent := field.parent
+	parentPath := field.parentPath
+	names := strings.Split(ref.path, ".")
+
+	for _, name := range names[:len(names)-1] {
+		structField, ok := parent.Type().FieldByName(name)
+		if ok {
+			parent, ok = fieldByIndex(parent, structField.Index)
+		}
+		if ok {
+			parent, ok = derefStruct(parent)
+		}
+		if !ok {
+			return Field{}, fmt.Errorf("%s: referenced field '%s' is not reachable",
+				field.Path, ref.path)
+		}
+
+		parentPath += name + "."
+	}
+
+	name := names[len(names)-1]
+
+	structField, ok := parent.Type().FieldByName(name)
+	if !ok {
+		return Field{}, fmt.Errorf("%s: referenced field '%s' not found",
+			field.Path, ref.path)
+	}
+
+	value, ok := fieldByIndex(parent, structField.Index)
+	if !ok {
+		return Field{}, fmt.Errorf("%s: referenced field '%s' is not reachable",
+			field.Path, ref.path)
+	}
+
+	return Field{
+		Value:      value,
+		Name:       name,
+		Path:       parentPath + name,
+		Kind:       structField.Type.Kind(),
+		Tags:       field.settings.fieldTags(parent.Type(), structField.Index),
+		parent:     parent,
+		parentPath: parentPath,
+		settings:   field.settings,
+	}, nil
+}
+
+// fieldTags returns compiled tags of struct typeOf field at index, nil if
+// field is not tagged or its tags are not valid.
+func (tg *TagSettings) fieldTags(typeOf reflect.Type, index []int) []Tag {
+	if tg == nil {
+		return nil
+	}
+
+	for _, fieldPlan := range tg.structPlan(typeOf).fields {
+		if fieldPlan.include && slices.Equal(fieldPlan.index, index) {
+			return slices.Clone(fieldPlan.tags)
+		}
+	}
+
+	return nil
+}
+
+// derefStruct dereferences pointers to struct, returns ok(false) on nil
+// pointer or if value is not a struct.
+func derefStruct(valueOf reflect.Value) (reflect.Value, bool) {
+	for valueOf.Kind() == reflect.Ptr {
+		if valueOf.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		valueOf = valueOf.Elem()
+	}
+
+	return valueOf, valueOf.Kind() == reflect.Struct
+}
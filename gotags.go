@@ -40,6 +40,7 @@ func NewTagSettings(
 		Processor:        processor,
 		IncludeNotTagged: includeNotTagged,
 		keysRequired:     nil,
+		plans:            newPlanCache(),
 	}
 	tg.keysRequired = tg.requiredKeys()
 	return tg
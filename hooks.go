@@ -0,0 +1,138 @@
+package gotags
+
+import (
+	"reflect"
+	"strings"
+)
+
+// BeforeTagParser is implemented by structs which need preparation before
+// their fields are parsed. It gets called for struct passed to ParseStruct
+// and for every nested struct being parsed.
+type BeforeTagParser interface {
+	BeforeTagParse() error
+}
+
+// AfterTagParser is implemented by structs which own rules spanning their
+// fields. It gets called after all field processors, with fields parsed from
+// the struct (including nested ones). Nested structs are called first.
+type AfterTagParser interface {
+	AfterTagParse(fields []Field) error
+}
+
+// TagFieldProcessor is implemented by field types which process their own
+// field. It gets called after field processors for every parsed field of
+// that type.
+type TagFieldProcessor interface {
+	ProcessTagField(field Field) error
+}
+
+var (
+	beforeTagParserType   = reflect.TypeOf((*BeforeTagParser)(nil)).Elem()
+	afterTagParserType    = reflect.TypeOf((*AfterTagParser)(nil)).Elem()
+	tagFieldProcessorType = reflect.TypeOf((*TagFieldProcessor)(nil)).Elem()
+)
+
+// structHooks tells which hook interfaces struct type implements.
+type structHooks struct {
+	before bool
+	after  bool
+}
+
+// pendingHook is AfterTagParse call waiting for field processors.
+// Fields of the struct are parsed fields from start to end.
+type pendingHook struct {
+	value  reflect.Value
+	prefix string
+	start  int
+	end    int
+}
+
+func typeHooks(typeOf reflect.Type) structHooks {
+	return structHooks{
+		before: implements(typeOf, beforeTagParserType),
+		after:  implements(typeOf, afterTagParserType),
+	}
+}
+
+// implements reports whether typeOf or pointer to it implements iface.
+func implements(typeOf, iface reflect.Type) bool {
+	if typeOf.Implements(iface) {
+		return true
+	}
+
+	return typeOf.Kind() != reflect.Ptr && reflect.PointerTo(typeOf).Implements(iface)
+}
+
+// hookReceiver returns value as interface, pointer to value if it is
+// addressable, so pointer receiver methods can be called.
+// Returns ok(false) for nil pointers and values which cannot be used.
+func hookReceiver(value reflect.Value) (any, bool) {
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		value = value.Addr()
+	}
+
+	if !value.IsValid() || !value.CanInterface() {
+		return nil, false
+	}
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, false
+	}
+
+	return value.Interface(), true
+}
+
+// callBeforeHook calls BeforeTagParse of struct valueOf.
+func callBeforeHook(valueOf reflect.Value, prefix string, collect bool) error {
+	receiver, ok := hookReceiver(valueOf)
+	if !ok {
+		return nil
+	}
+
+	hook, ok := receiver.(BeforeTagParser)
+	if !ok {
+		return nil
+	}
+
+	return hookError(hook.BeforeTagParse(), valueOf, prefix, collect)
+}
+
+// callAfterHook calls AfterTagParse of pending struct with its fields.
+func callAfterHook(pending pendingHook, fields []Field, collect bool) error {
+	receiver, ok := hookReceiver(pending.value)
+	if !ok {
+		return nil
+	}
+
+	hook, ok := receiver.(AfterTagParser)
+	if !ok {
+		return nil
+	}
+
+	return hookError(hook.AfterTagParse(fields), pending.value, pending.prefix, collect)
+}
+
+// callFieldHook calls ProcessTagField of field value.
+func callFieldHook(field Field) error {
+	receiver, ok := hookReceiver(field.Value)
+	if !ok {
+		return nil
+	}
+
+	hook, ok := receiver.(TagFieldProcessor)
+	if !ok {
+		return nil
+	}
+
+	return hook.ProcessTagField(field)
+}
+
+// hookError wraps struct hook error in collect mode.
+func hookError(err error, valueOf reflect.Value, prefix string, collect bool) error {
+	if err == nil || !collect {
+		return err
+	}
+
+	parseErr := newStructError(ErrProcessorFailed, valueOf.Type(), err)
+	parseErr.Field = strings.TrimSuffix(prefix, ".")
+	return parseErr
+}
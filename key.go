@@ -1,12 +1,254 @@
 package gotags
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // Validator can be used to validate key value pair.
 type Validator func(value string) error
 
+// ValidationContext holds data passed to ContextValidator.
+type ValidationContext struct {
+	Tag   Tag                 // Validated tag, Tag.Key is canonical key name.
+	Key   Key                 // Key definition of the tag.
+	Field reflect.StructField // Tagged field, zero for TagSettings.ParseStructTag.
+	Tags  []Tag               // All tags of the field, including Tag (read-only).
+}
+
+// Handler gets called by ParseStruct for every field which has the key,
+// with the field and the tag of that key.
+type Handler func(field Field, tag Tag) error
+
+// ContextValidator can be used to validate key value pair knowing the field
+// and its other tags, for example, to check that value fits field type.
+type ContextValidator func(ctx ValidationContext) error
+
+// ValueType defines type of key value. Tag values of typed keys are checked
+// and converted while parsing, converted value is available through Tag
+// accessors like Tag.Int() or Tag.Duration().
+type ValueType int
+
+const (
+	ValueString     ValueType = iota // Plain string, default.
+	ValueInt                         // Integer, Tag.Int().
+	ValueFloat                       // Floating point number, Tag.Float().
+	ValueBool                        // Boolean (strconv.ParseBool), Tag.Bool().
+	ValueDuration                    // time.Duration, Tag.Duration().
+	ValueRegexp                      // Regular expression, Tag.Regexp().
+	ValueEnum                        // One of Key.Enum values.
+	ValueStringList                  // List split by Key.ListSeparator, Tag.Strings().
+	ValueFieldRef                    // Path of other struct field, Tag.FieldRef().
+	ValueMap                         // Key/value entries split by Key.ListSeparator, Tag.Map().
+	ValueCall                        // Call syntax like oneof(a,b), Tag.Call().
+	ValueTags                        // Tags parsed by Key.Settings, Tag.Tags().
+)
+
+// DuplicatePolicy defines how repeated key in a single tag is handled,
+// like `gt:1;gt:5`.
+type DuplicatePolicy int
+
+const (
+	DuplicatesDefault    DuplicatePolicy = iota // Key uses TagSettings policy, settings accumulate.
+	DuplicatesAccumulate                        // Keep every tag, see Field.KeyValues.
+	DuplicatesReject                            // Fail with ErrDuplicateKey.
+	DuplicatesKeepLast                          // Keep only the last tag.
+)
+
+// defaultListSeparator is used for ValueStringList, ValueMap and ValueCall
+// keys without separator.
+const defaultListSeparator = ","
+
+// defaultMapEquals is used for ValueMap keys without equals.
+const defaultMapEquals = "="
+
 // Key holds data about specific key.
 type Key struct {
 	Validator
-	Name       string
-	IsBool     bool
-	IsRequired bool
+	Name          string
+	IsBool        bool
+	IsRequired    bool
+	Type          ValueType
+	Enum          []string // Allowed values of ValueEnum key.
+	ListSeparator string   // ValueStringList, ValueMap and ValueCall separator, default ",".
+	MapEquals     string   // ValueMap separator of entry key and value, default "=".
+	RefSeparator  string   // ValueFieldRef separator of path and the rest of value.
+	Aliases       []string // Alternative names, mapped to Name silently.
+	Deprecated    []string // Old names, mapped to Name with deprecation warning.
+	Duplicates    DuplicatePolicy
+	Kinds         []reflect.Kind // Field kinds key applies to, any if empty.
+	Types         []reflect.Type // Field types key applies to, any if empty.
+
+	// ContextValidator is optional validator which sees field context,
+	// runs after Validator.
+	ContextValidator ContextValidator
+
+	// Handler is optional key behaviour, see WithHandler.
+	Handler Handler
+
+	// Settings parses and validates ValueTags value, see WithTagSettings.
+	Settings *TagSettings
+}
+
+// WithType returns copy of key with declared value type.
+func (key Key) WithType(valueType ValueType) Key {
+	key.Type = valueType
+	return key
+}
+
+// WithEnum returns copy of key which accepts only one of passed values.
+func (key Key) WithEnum(values ...string) Key {
+	key.Type = ValueEnum
+	key.Enum = values
+	return key
+}
+
+// WithStringList returns copy of key which value is a list split by
+// separator. Escape character of TagSettings is respected while splitting.
+func (key Key) WithStringList(separator string) Key {
+	key.Type = ValueStringList
+	key.ListSeparator = separator
+	return key
+}
+
+// WithMap returns copy of key which value is a map of entries split by
+// separator, entry key and value are split by equals, like
+// `replace:old=new,a=b`. Escape character of TagSettings is respected.
+func (key Key) WithMap(separator, equals string) Key {
+	key.Type = ValueMap
+	key.ListSeparator = separator
+	key.MapEquals = equals
+	return key
+}
+
+// WithCall returns copy of key which value uses call syntax, like
+// `oneof(a,b,c)`, arguments are split by separator. Escape character of
+// TagSettings is respected.
+func (key Key) WithCall(separator string) Key {
+	key.Type = ValueCall
+	key.ListSeparator = separator
+	return key
+}
+
+// WithTagSettings returns copy of key which value is a tag of its own,
+// like `dive:min=1,max=5`. Value is parsed and validated by settings
+// (separator, equals, escape character and keys) while parent tag is parsed,
+// nested tags are available through Tag.Tags. Settings must not be changed
+// after key is added. Panics if settings is nil.
+func (key Key) WithTagSettings(settings *TagSettings) Key {
+	if settings == nil {
+		panic(fmt.Sprintf("gotags: key '%s' requires tag settings", key.Name))
+	}
+
+	key.Type = ValueTags
+	key.Settings = settings
+	return key
+}
+
+// WithFieldRef returns copy of key which value references other field of
+// the same struct, like `gtfield:StartDate` or `requiredIf:Type=admin`.
+// If separator is not empty, only value part before it is the field path,
+// the rest is available through Tag.FieldRef. Path can be nested, like
+// "Server.Port", and is checked against struct type by ParseStruct.
+func (key Key) WithFieldRef(separator string) Key {
+	key.Type = ValueFieldRef
+	key.RefSeparator = separator
+	return key
+}
+
+// WithDuplicates returns copy of key with own duplicate policy, overriding
+// TagSettings policy.
+func (key Key) WithDuplicates(policy DuplicatePolicy) Key {
+	key.Duplicates = policy
+	return key
+}
+
+// WithContextValidator returns copy of key with validator which gets field
+// context, see ValidationContext.
+func (key Key) WithContextValidator(validator ContextValidator) Key {
+	key.ContextValidator = validator
+	return key
+}
+
+// WithHandler returns copy of key with handler. ParseStruct calls handler
+// for every field which has the key, in tag order and before Processor.
+// Repeated key calls handler for every tag.
+func (key Key) WithHandler(handler Handler) Key {
+	key.Handler = handler
+	return key
+}
+
+// WithKinds returns copy of key which can be used only on fields of passed
+// kinds. Pointer fields are checked by their element kind.
+func (key Key) WithKinds(kinds ...reflect.Kind) Key {
+	key.Kinds = append(append([]reflect.Kind(nil), key.Kinds...), kinds...)
+	return key
+}
+
+// WithTypes returns copy of key which can be used only on fields of passed
+// types, like time.Duration. Pointer fields are checked by their element
+// type as well.
+func (key Key) WithTypes(types ...reflect.Type) Key {
+	key.Types = append(append([]reflect.Type(nil), key.Types...), types...)
+	return key
+}
+
+// WithAliases returns copy of key which also matches passed names.
+// Parsed tags always hold canonical key Name.
+func (key Key) WithAliases(names ...string) Key {
+	key.Aliases = append(append([]string(nil), key.Aliases...), names...)
+	return key
+}
+
+// WithDeprecated returns copy of key which also matches passed old names.
+// Parsed tags always hold canonical key Name and every use of old name is
+// reported to TagSettings deprecation handler, see WithDeprecationHandler.
+func (key Key) WithDeprecated(names ...string) Key {
+	key.Deprecated = append(append([]string(nil), key.Deprecated...), names...)
+	return key
+}
+
+// matchName reports whether name is key name, alias or deprecated name.
+func (key *Key) matchName(name string) (ok, deprecated bool) {
+	for _, alias := range key.Aliases {
+		if alias == name {
+			return true, false
+		}
+	}
+
+	for _, oldName := range key.Deprecated {
+		if oldName == name {
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+// checkFieldType returns error if key used as name is not applicable to
+// field of fieldType, see WithKinds and WithTypes.
+func (key *Key) checkFieldType(name string, fieldType reflect.Type) error {
+	if len(key.Kinds) == 0 && len(key.Types) == 0 {
+		return nil
+	}
+
+	elemType := fieldType
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	for _, kind := range key.Kinds {
+		if elemType.Kind() == kind {
+			return nil
+		}
+	}
+
+	for _, typeOf := range key.Types {
+		if fieldType == typeOf || elemType == typeOf {
+			return nil
+		}
+	}
+
+	return newParseError(ErrFieldTypeMismatch, name,
+		fmt.Errorf("tag '%s' cannot be used on field of type %s", name, fieldType))
 }
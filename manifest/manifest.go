@@ -0,0 +1,128 @@
+// Package manifest describes gotags.TagSettings in JSON, so tools can build
+// settings without Go code, for example, `gotags check`.
+//
+//	{
+//	  "tag": "validator",
+//	  "separator": ";",
+//	  "equals": ":",
+//	  "escape": "\\",
+//	  "keys": [
+//	    {"name": "required", "bool": true},
+//	    {"name": "gt", "required": false}
+//	  ]
+//	}
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gaigals/gotags"
+)
+
+// DefaultFilename is manifest filename used by tools when none is passed.
+const DefaultFilename = "gotags.json"
+
+// Manifest describes gotags.TagSettings.
+type Manifest struct {
+	Tag       string `json:"tag"`
+	Separator string `json:"separator,omitempty"` // Default ";".
+	Equals    string `json:"equals,omitempty"`    // Default ":".
+	Escape    string `json:"escape,omitempty"`    // Single character, disabled if empty.
+	Quote     string `json:"quote,omitempty"`     // Single character, disabled if empty.
+	Dynamic   bool   `json:"dynamic,omitempty"`   // Allow keys not listed in Keys.
+	Keys      []Key  `json:"keys"`
+}
+
+// Key describes gotags.Key.
+type Key struct {
+	Name     string `json:"name"`
+	Bool     bool   `json:"bool,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// Load reads manifest from JSON file.
+func Load(filename string) (*Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse parses manifest from JSON data.
+func Parse(data []byte) (*Manifest, error) {
+	var manifest Manifest
+
+	err := json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	err = manifest.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+func (manifest *Manifest) validate() error {
+	if manifest.Tag == "" {
+		return errors.New("manifest: tag name is required")
+	}
+	if len(manifest.Escape) > 1 {
+		return fmt.Errorf("manifest: escape '%s' must be single character",
+			manifest.Escape)
+	}
+	if len(manifest.Quote) > 1 {
+		return fmt.Errorf("manifest: quote '%s' must be single character",
+			manifest.Quote)
+	}
+
+	for idx, key := range manifest.Keys {
+		if key.Name == "" {
+			return fmt.Errorf("manifest: key %d has no name", idx)
+		}
+	}
+
+	return nil
+}
+
+// Settings creates gotags.TagSettings described by manifest.
+func (manifest *Manifest) Settings() *gotags.TagSettings {
+	separator, equals := manifest.Separator, manifest.Equals
+	if separator == "" {
+		separator = ";"
+	}
+	if equals == "" {
+		equals = ":"
+	}
+
+	tg := gotags.NewSettings(manifest.Tag).
+		WithCustomSeparators(separator, equals)
+
+	if manifest.Escape != "" {
+		tg.WithEscapeCharacter(manifest.Escape[0])
+	}
+	if manifest.Quote != "" {
+		tg.WithQuoteCharacter(manifest.Quote[0])
+	}
+	if manifest.Dynamic {
+		tg.WithNoKeyExistValidation()
+	}
+
+	for _, key := range manifest.Keys {
+		tg.AddKey(key.Key())
+	}
+
+	return tg
+}
+
+// Key creates gotags.Key described by key.
+func (key Key) Key() gotags.Key {
+	return gotags.NewKey(key.Name, key.Bool, key.Required, nil)
+}
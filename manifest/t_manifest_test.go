@@ -0,0 +1,62 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Run("Settings are created", func(t *testing.T) {
+		manifest, err := Parse([]byte(`{
+			"tag": "gotags",
+			"separator": ",",
+			"equals": "=",
+			"escape": "\\",
+			"keys": [
+				{"name": "required", "bool": true},
+				{"name": "min", "required": true}
+			]
+		}`))
+		testza.AssertNoError(t, err, "unexpected error")
+
+		tg := manifest.Settings()
+		testza.AssertEqual(t, tg.Name, "gotags", "unexpected tag name")
+		testza.AssertEqual(t, tg.Separator, ",", "unexpected separator")
+		testza.AssertEqual(t, tg.Equals, "=", "unexpected equals")
+		testza.AssertLen(t, tg.Keys, 2, "unexpected keys len")
+		testza.AssertTrue(t, tg.Keys[0].IsBool, "expected bool key")
+		testza.AssertTrue(t, tg.Keys[1].IsRequired, "expected required key")
+
+		data := struct {
+			Name string `gotags:"required,min=1\\,2"`
+		}{}
+
+		fields, err := tg.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, fields[0].KeyValue("min"), "1,2",
+			"unexpected escaped value")
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		manifest, err := Parse([]byte(`{"tag": "validator"}`))
+		testza.AssertNoError(t, err, "unexpected error")
+
+		tg := manifest.Settings()
+		testza.AssertEqual(t, tg.Separator, ";", "unexpected separator")
+		testza.AssertEqual(t, tg.Equals, ":", "unexpected equals")
+	})
+
+	t.Run("Invalid manifests", func(t *testing.T) {
+		for _, data := range []string{
+			`{`,
+			`{"keys": []}`,
+			`{"tag": "x", "escape": "ab"}`,
+			`{"tag": "x", "quote": "ab"}`,
+			`{"tag": "x", "keys": [{"bool": true}]}`,
+		} {
+			_, err := Parse([]byte(data))
+			testza.AssertNotNil(t, err, "expected error for "+data)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package gotags
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// convertTags parses value with key settings. Value is already unescaped by
+// parent layer, key settings unescape their own layer.
+func (key *Key) convertTags(value string) (any, error) {
+	if key.Settings == nil {
+		return nil, fmt.Errorf("tag '%s' has no tag settings", key.Name)
+	}
+
+	tags, err := key.Settings.parseTagValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("tag '%s' value is invalid: %w", key.Name, err)
+	}
+
+	return tags, nil
+}
+
+// parseTagValue parses and validates value as tag content of tg, like
+// ParseStructTag does with tag content. Offsets of errors point into value.
+func (tg *TagSettings) parseTagValue(value string) ([]Tag, error) {
+	var errs []*ParseError
+
+	tags, err := tg.parseTagString(value)
+	if err != nil {
+		errs = []*ParseError{tg.locateParseError(asParseError(err), nil, value)}
+	} else {
+		tags, errs = tg.compileTagContent(nil, reflect.StructField{}, value, tags)
+	}
+
+	if len(errs) == 0 {
+		errs = tg.checkKeyRules(nil, value, tags)
+	}
+
+	if len(errs) == 0 {
+		return tags, nil
+	}
+
+	return nil, joinParseErrors(errs)
+}
+
+// Tags returns nested tags of ValueTags key, like tags "min" and "max" of
+// `dive:min=1,max=5`, returned tags are copies. Returns nil if key is not
+// declared with tag settings.
+func (tag Tag) Tags() []Tag {
+	converted, _ := tag.converted().([]Tag)
+	return slices.Clone(converted)
+}
@@ -0,0 +1,299 @@
+package gotags
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// planCache holds compiled struct plans per reflect.Type.
+// Tags of a type never change, so every struct type gets compiled only once
+// per TagSettings and later ParseStruct calls only bind reflect.Values.
+// Plans are compiled with exported settings of the first parse, they are not
+// checked again, see TagSettings.ResetCache.
+type planCache struct {
+	plans sync.Map // planKey => *structPlan
+}
+
+// planKey is key of compiled plan. Copies of TagSettings share planCache,
+// so plans are kept per TagSettings they were compiled by.
+type planKey struct {
+	settings *TagSettings
+	typeOf   reflect.Type
+}
+
+// structPlan is compiled parse result of a struct type.
+type structPlan struct {
+	fields   []fieldPlan
+	hooks    structHooks
+	tagCount int // Tags of all included fields.
+}
+
+// fieldPlan is compiled parse result of a single struct field.
+type fieldPlan struct {
+	index   []int // Index sequence, see fieldByIndex.
+	name    string
+	kind    reflect.Kind
+	tags    []Tag
+	errs    []*ParseError // Tag parsing and validation errors.
+	include bool          // Field is returned by ParseStruct.
+	descend bool          // Field is nested struct or container of structs.
+	hook    bool          // Field type implements TagFieldProcessor.
+}
+
+func newPlanCache() *planCache {
+	return &planCache{}
+}
+
+// resetPlans drops compiled plans, must be called by every method which
+// changes parsing behavior.
+func (tg *TagSettings) resetPlans() {
+	tg.plans = newPlanCache()
+}
+
+// ResetCache drops compiled struct plans. Must be called after exported
+// fields (like Keys or key validators) are changed directly, methods drop
+// plans by themselves.
+func (tg *TagSettings) ResetCache() {
+	tg.resetPlans()
+}
+
+// structPlan returns compiled plan for typeOf, compiling it on first use.
+func (tg *TagSettings) structPlan(typeOf reflect.Type) *structPlan {
+	if tg.plans == nil {
+		return tg.compileStructPlan(typeOf)
+	}
+
+	key := planKey{settings: tg, typeOf: typeOf}
+	if plan, ok := tg.plans.plans.Load(key); ok {
+		return plan.(*structPlan)
+	}
+
+	plan, _ := tg.plans.plans.LoadOrStore(key, tg.compileStructPlan(typeOf))
+	return plan.(*structPlan)
+}
+
+func (tg *TagSettings) compileStructPlan(typeOf reflect.Type) *structPlan {
+	infos := tg.structFields(typeOf)
+	plan := &structPlan{
+		fields: make([]fieldPlan, 0, len(infos)),
+		hooks:  typeHooks(typeOf),
+	}
+
+	for _, info := range infos {
+		structField := info.field
+
+		fieldPlan := fieldPlan{
+			index:   info.index,
+			name:    structField.Name,
+			kind:    structField.Type.Kind(),
+			descend: tg.isDescendable(structField.Type),
+			hook:    implements(structField.Type, tagFieldProcessorType),
+		}
+
+		fieldPlan.tags, fieldPlan.errs = tg.compileTags(typeOf, structField)
+		fieldPlan.include = len(fieldPlan.errs) == 0 &&
+			(len(fieldPlan.tags) > 0 || tg.IncludeNotTagged)
+
+		if fieldPlan.include {
+			fieldPlan.errs = tg.checkKeyRules(typeOf, structField.Tag.Get(tg.Name),
+				fieldPlan.tags)
+			fieldPlan.include = len(fieldPlan.errs) == 0
+		}
+
+		if fieldPlan.include {
+			plan.tagCount += len(fieldPlan.tags)
+		}
+
+		plan.fields = append(plan.fields, fieldPlan)
+	}
+
+	return plan
+}
+
+// bindTags copies compiled tags to the end of buffer and returns the copy.
+// Copy capacity is limited, so appending to it does not change tags of other
+// fields sharing buffer.
+func (fieldPlan *fieldPlan) bindTags(buffer *[]Tag) []Tag {
+	if len(fieldPlan.tags) == 0 {
+		return fieldPlan.tags
+	}
+
+	start := len(*buffer)
+	*buffer = append(*buffer, fieldPlan.tags...)
+
+	return (*buffer)[start:len(*buffer):len(*buffer)]
+}
+
+// compileTags reads and validates tag content of struct typeOf field.
+// Returns every problem found in tag, deprecated key names are reported to
+// deprecation handler. Field type is not checked if structField.Type is nil.
+func (tg *TagSettings) compileTags(
+	typeOf reflect.Type,
+	structField reflect.StructField,
+) ([]Tag, []*ParseError) {
+	raw, _ := structField.Tag.Lookup(tg.Name)
+
+	tags, err := tg.readTagContent(structField.Tag)
+	if err != nil {
+		return nil, []*ParseError{
+			tg.locateParseError(asParseError(err), typeOf, raw),
+		}
+	}
+
+	return tg.compileTagContent(typeOf, structField, raw, tags)
+}
+
+// compileTagContent validates tags read from raw tag content of struct
+// typeOf field, see compileTags.
+func (tg *TagSettings) compileTagContent(
+	typeOf reflect.Type,
+	structField reflect.StructField,
+	raw string,
+	tags []Tag,
+) ([]Tag, []*ParseError) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	errs, deprecations := tg.validateTags(tags, structField)
+	tg.warnDeprecated(typeOf, structField.Name, deprecations)
+
+	if typeOf != nil {
+		errs = append(errs, tg.checkFieldRefs(typeOf, tags)...)
+	}
+
+	tags, duplicateErrs := tg.checkDuplicates(tags)
+	errs = append(errs, duplicateErrs...)
+
+	if len(errs) == 0 {
+		return tags, nil
+	}
+
+	parseErrs := make([]*ParseError, len(errs))
+	for idx, err := range errs {
+		parseErrs[idx] = tg.locateParseError(asParseError(err), typeOf, raw)
+	}
+
+	return nil, parseErrs
+}
+
+// warnDeprecated passes deprecated key name uses to deprecation handler.
+func (tg *TagSettings) warnDeprecated(
+	typeOf reflect.Type,
+	fieldName string,
+	deprecations []Deprecation,
+) {
+	if tg.deprecationHandler == nil {
+		return
+	}
+
+	for _, deprecation := range deprecations {
+		deprecation.Type = typeOf
+		deprecation.Field = fieldName
+		tg.deprecationHandler(deprecation)
+	}
+}
+
+// checkKeyRules returns error for every missing required key and every
+// violated constraint.
+func (tg *TagSettings) checkKeyRules(
+	typeOf reflect.Type,
+	raw string,
+	tags []Tag,
+) []*ParseError {
+	return append(
+		tg.checkRequiredKeys(typeOf, raw, tags),
+		tg.checkConstraints(typeOf, raw, tags)...,
+	)
+}
+
+// checkRequiredKeys returns error for every missing required key.
+func (tg *TagSettings) checkRequiredKeys(
+	typeOf reflect.Type,
+	raw string,
+	tags []Tag,
+) []*ParseError {
+	missingKeys := tg.missingRequiredKeys(tags)
+	if len(missingKeys) == 0 {
+		return nil
+	}
+
+	parseErrs := make([]*ParseError, len(missingKeys))
+
+	for idx, missingKey := range missingKeys {
+		parseErr := newParseError(ErrRequiredKeyMissing, missingKey,
+			fmt.Errorf("key '%s' is required but not found", missingKey))
+		parseErrs[idx] = tg.locateParseError(parseErr, typeOf, raw)
+	}
+
+	return parseErrs
+}
+
+// locateParseError fills struct, tag and raw tag data of parseErr and
+// calculates offset of the problem in raw tag string.
+func (tg *TagSettings) locateParseError(
+	parseErr *ParseError,
+	typeOf reflect.Type,
+	raw string,
+) *ParseError {
+	parseErr.Type = typeOf
+	parseErr.Tag = tg.Name
+	parseErr.Raw = raw
+
+	switch {
+	case parseErr.Kind == ErrTrailingEscape:
+		parseErr.Offset = len(raw) - 1
+	case parseErr.tagIndex >= 0:
+		parseErr.Offset = tg.tagOffset(raw, parseErr.tagIndex, parseErr.Kind)
+	}
+
+	return parseErr
+}
+
+// tagOffset returns offset of tag at index in raw tag string. For value
+// related problems offset points to the value (or the end of the tag if
+// value is missing).
+func (tg *TagSettings) tagOffset(raw string, index int, kind error) int {
+	start := 0
+
+	for i := 0; i < index; i++ {
+		separatorIndex := tg.indexSeparator(raw[start:])
+		if separatorIndex < 0 {
+			break
+		}
+
+		start += separatorIndex + len(tg.Separator)
+	}
+
+	end := len(raw)
+	if separatorIndex := tg.indexSeparator(raw[start:]); separatorIndex >= 0 {
+		end = start + separatorIndex
+	}
+
+	switch kind {
+	case ErrUnexpectedArgument, ErrMissingArgument, ErrInvalidValue, ErrUnterminatedQuote:
+		equalsIndex := indexUnescaped(raw[start:end], tg.Equals, tg.escapeCharacter)
+		if equalsIndex < 0 {
+			return end
+		}
+
+		return start + equalsIndex + len(tg.Equals)
+	default:
+		return start
+	}
+}
+
+// isDescendable reports whether field of typeOf must be walked into.
+func (tg *TagSettings) isDescendable(typeOf reflect.Type) bool {
+	switch typeOf.Kind() {
+	case reflect.Struct:
+		return tg.recursive
+	case reflect.Ptr:
+		return tg.recursive && typeOf.Elem().Kind() == reflect.Struct
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return tg.traverseContainers && hasStructElements(typeOf)
+	default:
+		return false
+	}
+}
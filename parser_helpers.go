@@ -1,13 +1,10 @@
 package gotags
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 )
 
-var errTrailingBackslash = errors.New("trailing naked backslash")
-
 // SplitWithEscape splits by the current layer separator while respecting the
 // configured escape character. It only unescapes the current separator and
 // escaped backslashes, leaving deeper escapes for later parsing layers.
@@ -279,7 +276,7 @@ func unescapeCurrentLayerCharacters(
 		}
 
 		if index+1 >= len(input) {
-			return "", fmt.Errorf("%w in %q", errTrailingBackslash, input)
+			return "", fmt.Errorf("%w in %q", ErrTrailingEscape, input)
 		}
 
 		tokenLength := currentLayerEscapedTokenLength(
@@ -327,7 +324,7 @@ func unescapeCurrentLayerCharactersFromIndex(
 		}
 
 		if index+1 >= len(input) {
-			return "", fmt.Errorf("%w in %q", errTrailingBackslash, input)
+			return "", fmt.Errorf("%w in %q", ErrTrailingEscape, input)
 		}
 
 		tokenLength = currentLayerEscapedTokenLength(
@@ -379,7 +376,7 @@ func nextCurrentLayerEscapedTokenLength(
 	}
 
 	if index+1 >= len(input) {
-		return 0, fmt.Errorf("%w in %q", errTrailingBackslash, input)
+		return 0, fmt.Errorf("%w in %q", ErrTrailingEscape, input)
 	}
 
 	return currentLayerEscapedTokenLength(
@@ -439,3 +436,35 @@ func containsEscapeCharacter(input string, escapeCharacter byte) bool {
 
 	return strings.IndexByte(input, escapeCharacter) >= 0
 }
+
+// indexUnescaped returns index of the first separator in input which is not
+// escaped, -1 if there is none. It skips escapes the same way the splitters
+// do, but never fails.
+func indexUnescaped(input, separator string, escapeCharacter byte) int {
+	if separator == "" {
+		return -1
+	}
+
+	for index := 0; index < len(input); {
+		if escapeCharacter != 0 && input[index] == escapeCharacter {
+			if index+1 >= len(input) {
+				return -1
+			}
+
+			index += 1 + currentLayerEscapedTokenLength(
+				input[index+1:],
+				separator,
+				escapeCharacter,
+			)
+			continue
+		}
+
+		if strings.HasPrefix(input[index:], separator) {
+			return index
+		}
+
+		index++
+	}
+
+	return -1
+}
@@ -0,0 +1,170 @@
+package gotags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quoted values work next to escapes: value starting with quote character
+// right after equals is read up to the closing quote, separators, equals and
+// escape characters inside are literal. Quote itself is written doubled.
+// Only values can be quoted, quote inside key or unquoted value is literal.
+
+// splitQuoted splits input by separator, skipping separators of quoted
+// values and escaped separators. Parts are returned raw, escapes are
+// removed later by newTagFromQuoted.
+func splitQuoted(
+	input,
+	separator,
+	equals string,
+	quote,
+	escapeCharacter byte,
+) ([]string, error) {
+	parts := make([]string, 0, strings.Count(input, separator)+1)
+
+	for {
+		end, err := quotedPartEnd(input, separator, equals, quote, escapeCharacter)
+		if err != nil {
+			return nil, withTagIndex(err, len(parts))
+		}
+
+		parts = append(parts, input[:end])
+		if end == len(input) {
+			return parts, nil
+		}
+
+		input = input[end+len(separator):]
+	}
+}
+
+// quotedPartEnd returns index of the first separator in input which is not
+// escaped and not part of quoted value, len(input) if there is none.
+func quotedPartEnd(
+	input,
+	separator,
+	equals string,
+	quote,
+	escapeCharacter byte,
+) (int, error) {
+	end := indexUnescaped(input, separator, escapeCharacter)
+	if end < 0 {
+		end = len(input)
+	}
+
+	valueStart, ok := quotedValueStart(input[:end], equals, quote, escapeCharacter)
+	if !ok {
+		return end, nil
+	}
+
+	valueEnd, err := closingQuoteEnd(input, valueStart, quote)
+	if err != nil {
+		return 0, err
+	}
+
+	if valueEnd < len(input) && !strings.HasPrefix(input[valueEnd:], separator) {
+		return 0, fmt.Errorf("unexpected characters after quoted value in %q", input)
+	}
+
+	return valueEnd, nil
+}
+
+// quotedValueStart returns index of opening quote of tag value, ok(false) if
+// value is not quoted.
+func quotedValueStart(
+	input,
+	equals string,
+	quote,
+	escapeCharacter byte,
+) (int, bool) {
+	equalsIndex := indexUnescaped(input, equals, escapeCharacter)
+	if equalsIndex < 0 {
+		return 0, false
+	}
+
+	valueStart := equalsIndex + len(equals)
+	return valueStart, valueStart < len(input) && input[valueStart] == quote
+}
+
+// closingQuoteEnd returns index right after closing quote of quoted value
+// starting at start.
+func closingQuoteEnd(input string, start int, quote byte) (int, error) {
+	for index := start + 1; index < len(input); index++ {
+		if input[index] != quote {
+			continue
+		}
+
+		if index+1 < len(input) && input[index+1] == quote {
+			index++
+			continue
+		}
+
+		return index + 1, nil
+	}
+
+	return 0, fmt.Errorf("%w in %q", ErrUnterminatedQuote, input[start:])
+}
+
+// newTagFromQuoted creates tag of part returned by splitQuoted.
+func newTagFromQuoted(
+	part,
+	separator,
+	equals string,
+	quote,
+	escapeCharacter byte,
+) (Tag, error) {
+	valueStart, ok := quotedValueStart(part, equals, quote, escapeCharacter)
+	if !ok {
+		unescaped, err := unescapeCurrentLayerCharacters(part, separator, escapeCharacter)
+		if err != nil {
+			return Tag{}, err
+		}
+
+		return newTagFromString(unescaped, equals, escapeCharacter)
+	}
+
+	key, err := unescapeCurrentLayerCharacters(
+		part[:valueStart-len(equals)],
+		separator,
+		escapeCharacter,
+	)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	key, err = unescapeCurrentLayerCharacters(key, equals, escapeCharacter)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	quoteString := string(quote)
+	value := part[valueStart+1 : len(part)-1]
+
+	return Tag{
+		Key:   key,
+		Value: strings.ReplaceAll(value, quoteString+quoteString, quoteString),
+	}, nil
+}
+
+// indexSeparator returns index of the first separator in raw tag string
+// which separates tags, -1 if there is none.
+func (tg *TagSettings) indexSeparator(raw string) int {
+	if !tg.usesQuotes(raw) {
+		return indexUnescaped(raw, tg.Separator, tg.escapeCharacter)
+	}
+
+	end, err := quotedPartEnd(raw, tg.Separator, tg.Equals, tg.quoteCharacter,
+		tg.escapeCharacter)
+	if err != nil || end == len(raw) {
+		return -1
+	}
+
+	return end
+}
+
+// usesQuotes reports whether tagString must be parsed with quote support.
+func (tg *TagSettings) usesQuotes(tagString string) bool {
+	return tg.quoteCharacter != 0 &&
+		tg.Separator != "" &&
+		tg.Equals != "" &&
+		strings.IndexByte(tagString, tg.quoteCharacter) >= 0
+}
@@ -0,0 +1,413 @@
+// Package source parses struct tags straight from Go source files with
+// go/parser, without reflection and without struct instances.
+// Tags are split and validated by gotags.TagSettings, so results and errors
+// match gotags.TagSettings.ParseStruct, but point to source positions.
+package source
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gaigals/gotags"
+)
+
+// Struct is struct type declared in source.
+type Struct struct {
+	Name   string
+	Pos    token.Position
+	Fields []Field
+}
+
+// Field is struct field tagged with TagSettings name.
+type Field struct {
+	Name string         // Field path, inline struct fields are dotted, like "Server.Host".
+	Pos  token.Position // Position of field name.
+	Raw  string         // Raw tag content, like "gt:10;lt:130".
+	Tags []gotags.Tag
+}
+
+// Error is tag problem at source position.
+type Error struct {
+	Pos token.Position
+	Err error // *gotags.ParseError
+}
+
+// Error returns error message prefixed with source position.
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s: %v", err.Pos, err.Err)
+}
+
+// Unwrap returns underlying error.
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// ErrorList is list of tag problems, like go/scanner.ErrorList.
+type ErrorList []*Error
+
+// Error returns the first error and count of others.
+func (list ErrorList) Error() string {
+	switch len(list) {
+	case 0:
+		return "no errors"
+	case 1:
+		return list[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", list[0], len(list)-1)
+	}
+}
+
+// Unwrap returns all errors.
+func (list ErrorList) Unwrap() []error {
+	errs := make([]error, len(list))
+	for idx, err := range list {
+		errs[idx] = err
+	}
+	return errs
+}
+
+// ParseFile parses single Go source file and returns its structs with tags
+// of tg. src is passed to go/parser.ParseFile, if nil file gets read from
+// filename. Tag problems are returned as ErrorList.
+func ParseFile(
+	tg *gotags.TagSettings,
+	filename string,
+	src any,
+) ([]Struct, error) {
+	fileSet := token.NewFileSet()
+
+	file, err := parser.ParseFile(fileSet, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseASTFile(tg, fileSet, file)
+}
+
+// ParseDir parses all Go files (excluding _test.go files) in dir, not
+// recursively. Tag problems are returned as ErrorList.
+func ParseDir(tg *gotags.TagSettings, dir string) ([]Struct, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		filenames = append(filenames, filepath.Join(dir, name))
+	}
+
+	return ParseFiles(tg, filenames...)
+}
+
+// ParseFiles parses passed Go files. Tag problems of all files are returned
+// as single ErrorList.
+func ParseFiles(tg *gotags.TagSettings, filenames ...string) ([]Struct, error) {
+	var (
+		structs []Struct
+		errList ErrorList
+	)
+
+	for _, filename := range filenames {
+		fileStructs, err := ParseFile(tg, filename, nil)
+
+		var fileErrList ErrorList
+		if errors.As(err, &fileErrList) {
+			errList = append(errList, fileErrList...)
+		} else if err != nil {
+			return nil, err
+		}
+
+		structs = append(structs, fileStructs...)
+	}
+
+	if len(errList) > 0 {
+		return structs, errList
+	}
+
+	return structs, nil
+}
+
+func parseASTFile(
+	tg *gotags.TagSettings,
+	fileSet *token.FileSet,
+	file *ast.File,
+) ([]Struct, error) {
+	var (
+		structs []Struct
+		errList ErrorList
+	)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		typeSpec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		parsed := Struct{
+			Name: typeSpec.Name.Name,
+			Pos:  fileSet.Position(typeSpec.Name.Pos()),
+		}
+
+		errList = parseFields(tg, fileSet, structType, "", &parsed, errList)
+		structs = append(structs, parsed)
+		return true
+	})
+
+	sort.SliceStable(errList, func(i, j int) bool {
+		return errList[i].Pos.Offset < errList[j].Pos.Offset
+	})
+
+	if len(errList) > 0 {
+		return structs, errList
+	}
+
+	return structs, nil
+}
+
+// parseFields parses fields of structType, inline struct types are parsed
+// with prefixed field names.
+func parseFields(
+	tg *gotags.TagSettings,
+	fileSet *token.FileSet,
+	structType *ast.StructType,
+	prefix string,
+	parsed *Struct,
+	errList ErrorList,
+) ErrorList {
+	for _, astField := range structType.Fields.List {
+		for _, name := range fieldNames(astField) {
+			if !ast.IsExported(name.Name) {
+				continue
+			}
+
+			path := prefix + name.Name
+			errList = parseField(tg, fileSet, astField, name, path, parsed, errList)
+
+			if inline, ok := astField.Type.(*ast.StructType); ok {
+				errList = parseFields(tg, fileSet, inline, path+".", parsed, errList)
+			}
+		}
+	}
+
+	return errList
+}
+
+func parseField(
+	tg *gotags.TagSettings,
+	fileSet *token.FileSet,
+	astField *ast.Field,
+	name *ast.Ident,
+	path string,
+	parsed *Struct,
+	errList ErrorList,
+) ErrorList {
+	literal := ""
+	if astField.Tag != nil {
+		literal = astField.Tag.Value
+	}
+
+	tagContent, err := strconv.Unquote(literal)
+	if err != nil {
+		tagContent = ""
+	}
+
+	structTag := reflect.StructTag(tagContent)
+	raw, ok := structTag.Lookup(tg.Name)
+	if !ok && !tg.IncludeNotTagged {
+		return errList
+	}
+
+	tags, err := tg.ParseStructTag(structTag)
+	if err != nil {
+		for _, parseErr := range parseErrors(err) {
+			parseErr.Field = path
+			errList = append(errList, &Error{
+				Pos: tagPosition(fileSet, name, astField.Tag, tg.Name, parseErr.Offset),
+				Err: parseErr,
+			})
+		}
+
+		return errList
+	}
+
+	parsed.Fields = append(parsed.Fields, Field{
+		Name: path,
+		Pos:  fileSet.Position(name.Pos()),
+		Raw:  raw,
+		Tags: tags,
+	})
+
+	return errList
+}
+
+// fieldNames returns names of field, embedded field is named by its type.
+func fieldNames(astField *ast.Field) []*ast.Ident {
+	if len(astField.Names) > 0 {
+		return astField.Names
+	}
+
+	typeExpr := astField.Type
+	for {
+		switch expr := typeExpr.(type) {
+		case *ast.StarExpr:
+			typeExpr = expr.X
+		case *ast.SelectorExpr:
+			return []*ast.Ident{expr.Sel}
+		case *ast.IndexExpr:
+			typeExpr = expr.X
+		case *ast.IndexListExpr:
+			typeExpr = expr.X
+		case *ast.Ident:
+			return []*ast.Ident{expr}
+		default:
+			return nil
+		}
+	}
+}
+
+func parseErrors(err error) []*gotags.ParseError {
+	var errs []error
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+
+	parseErrs := make([]*gotags.ParseError, 0, len(errs))
+
+	for _, err := range errs {
+		var parseErr *gotags.ParseError
+		if errors.As(err, &parseErr) {
+			errCopy := *parseErr
+			parseErrs = append(parseErrs, &errCopy)
+		}
+	}
+
+	return parseErrs
+}
+
+// tagPosition returns source position of offset in tag content with name.
+// Offsets are mapped exactly for raw string (backquoted) tags, otherwise
+// position of tag literal is returned. Field without tag gets position of
+// its name.
+func tagPosition(
+	fileSet *token.FileSet,
+	field *ast.Ident,
+	tag *ast.BasicLit,
+	name string,
+	offset int,
+) token.Position {
+	if tag == nil {
+		return fileSet.Position(field.Pos())
+	}
+
+	position := fileSet.Position(tag.Pos())
+	literal := tag.Value
+
+	if offset < 0 || !strings.HasPrefix(literal, "`") {
+		return position
+	}
+
+	valueStart, ok := lookupValueOffset(literal[1:len(literal)-1], name)
+	if !ok {
+		return position
+	}
+
+	shift := 1 + valueStart + quotedOffset(literal[1+valueStart:], offset)
+	position.Offset += shift
+	position.Column += shift
+
+	return position
+}
+
+// lookupValueOffset works like reflect.StructTag.Lookup, but returns offset
+// of quoted value (after opening quote) in tag.
+func lookupValueOffset(tag, key string) (int, bool) {
+	start := 0
+
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		start += i
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		start += i + 2
+		tag = tag[i+2:]
+
+		i = 0
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+
+		if name == key {
+			return start, true
+		}
+
+		start += i + 1
+		tag = tag[i+1:]
+	}
+
+	return 0, false
+}
+
+// quotedOffset maps offset in unquoted value to offset in quoted value.
+func quotedOffset(quoted string, unquotedOffset int) int {
+	offset := 0
+	unquoted := 0
+
+	for unquoted < unquotedOffset && quoted != "" && quoted[0] != '"' {
+		value, _, tail, err := strconv.UnquoteChar(quoted, '"')
+		if err != nil {
+			break
+		}
+
+		offset += len(quoted) - len(tail)
+		unquoted += len(string(value))
+		quoted = tail
+	}
+
+	return offset
+}
@@ -0,0 +1,130 @@
+package source
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/gaigals/gotags"
+)
+
+// testSource uses ~ instead of backquote.
+var testSource = strings.ReplaceAll(`package models
+
+type User struct {
+	Name    string ~validator:"required" json:"name"~
+	Age     uint   ~validator:"gt:10;lt:130"~
+	Country string
+	Server  struct {
+		Host string ~validator:"required"~
+	}
+}
+
+type Broken struct {
+	Age   int ~validator:"gt:10;eq:1"~
+	Regex int ~validator:"gt:a\\;b;lt"~
+}
+`, "~", "`")
+
+func newTestSettings() *gotags.TagSettings {
+	return gotags.NewSettings("validator").
+		WithEscapeCharacter('\\').
+		AddKeys(
+			gotags.NewKey("required", true, false, nil),
+			gotags.NewKey("gt", false, false, nil),
+			gotags.NewKey("lt", false, false, nil),
+		)
+}
+
+func Test_ParseFile(t *testing.T) {
+	structs, err := ParseFile(newTestSettings(), "models.go", testSource)
+	testza.AssertNotNil(t, err, "expected error")
+	testza.AssertLen(t, structs, 2, "unexpected structs len")
+
+	user := structs[0]
+	testza.AssertEqual(t, user.Name, "User", "unexpected struct name")
+	testza.AssertEqual(t, user.Pos.Line, 3, "unexpected struct line")
+	testza.AssertLen(t, user.Fields, 3, "unexpected fields len")
+
+	testza.AssertEqual(t, user.Fields[0].Name, "Name", "unexpected field name")
+	testza.AssertEqual(t, user.Fields[0].Pos.Line, 4, "unexpected field line")
+	testza.AssertEqual(t, user.Fields[0].Tags, []gotags.Tag{{Key: "required"}},
+		"unexpected tags")
+
+	testza.AssertEqual(t, user.Fields[1].Raw, "gt:10;lt:130", "unexpected raw")
+	testza.AssertEqual(t, user.Fields[1].Tags, []gotags.Tag{
+		{Key: "gt", Value: "10"},
+		{Key: "lt", Value: "130"},
+	}, "unexpected tags")
+
+	testza.AssertEqual(t, user.Fields[2].Name, "Server.Host",
+		"unexpected inline field name")
+
+	var errList ErrorList
+	testza.AssertTrue(t, errors.As(err, &errList), "expected ErrorList")
+	testza.AssertLen(t, errList, 2, "unexpected errors len")
+
+	// Tag literal starts at column 12, eq is 18 bytes later.
+	testza.AssertEqual(t, errList[0].Pos.String(), "models.go:13:30",
+		"unexpected error position")
+	testza.AssertTrue(t, errors.Is(errList[0], gotags.ErrUnknownKey),
+		"unexpected error kind")
+
+	// Missing lt argument is at the end of the tag value. Escaped backslash
+	// takes 2 bytes in source and 1 in tag value.
+	testza.AssertEqual(t, errList[1].Pos.String(), "models.go:14:35",
+		"unexpected error position")
+	testza.AssertTrue(t, errors.Is(errList[1], gotags.ErrMissingArgument),
+		"unexpected error kind")
+
+	var parseErr *gotags.ParseError
+	testza.AssertTrue(t, errors.As(errList[0], &parseErr), "expected ParseError")
+	testza.AssertEqual(t, parseErr.Field, "Age", "unexpected field")
+	testza.AssertContains(t, errList[0].Error(),
+		"models.go:13:30: field 'Age': tag 'eq' does not exist",
+		"unexpected message")
+}
+
+func Test_ParseDir(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"models.go":      testSource,
+		"models_test.go": "package models\n\ntype T struct {\n\tA int `validator:\"x\"`\n}\n",
+		"notes.txt":      "not go",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		testza.AssertNoError(t, err, "unexpected error")
+	}
+
+	structs, err := ParseDir(newTestSettings(), dir)
+	testza.AssertLen(t, structs, 2, "unexpected structs len")
+
+	var errList ErrorList
+	testza.AssertTrue(t, errors.As(err, &errList), "expected ErrorList")
+	testza.AssertLen(t, errList, 2, "unexpected errors len")
+	testza.AssertEqual(t, errList[0].Pos.Filename, filepath.Join(dir, "models.go"),
+		"unexpected filename")
+}
+
+func Test_ParseFileNotTagged(t *testing.T) {
+	tagSettings := gotags.NewSettings("validator").
+		AddKey(gotags.NewKey("required", true, true, nil))
+	tagSettings.IncludeNotTagged = true
+
+	src := "package models\n\ntype User struct {\n\tName string\n}\n"
+
+	_, err := ParseFile(tagSettings, "models.go", src)
+
+	var errList ErrorList
+	testza.AssertTrue(t, errors.As(err, &errList), "expected ErrorList")
+	testza.AssertLen(t, errList, 1, "unexpected errors len")
+	testza.AssertEqual(t, errList[0].Pos.String(), "models.go:4:2",
+		"field without tag must point to field name")
+	testza.AssertTrue(t, errors.Is(errList[0], gotags.ErrRequiredKeyMissing),
+		"unexpected error kind")
+}
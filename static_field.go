@@ -0,0 +1,83 @@
+package gotags
+
+import (
+	"reflect"
+	"slices"
+)
+
+// StaticField is struct field with pre-parsed tags and typed access to its
+// value. It is built by code generated with `gotags generate`, so getting
+// field tags or value needs no tag parsing and no reflection.
+type StaticField[T any] struct {
+	Name string // Field name
+	Tags []Tag  // Pre-parsed field tag data (shared between calls, read-only)
+
+	ptr *T
+}
+
+// NewStaticField creates StaticField of field pointed by ptr.
+func NewStaticField[T any](name string, ptr *T, tags []Tag) StaticField[T] {
+	return StaticField[T]{
+		Name: name,
+		Tags: tags,
+		ptr:  ptr,
+	}
+}
+
+// Get returns field value.
+func (field StaticField[T]) Get() T {
+	return *field.ptr
+}
+
+// Set sets new value for field.
+func (field StaticField[T]) Set(value T) {
+	*field.ptr = value
+}
+
+// Ptr returns pointer to field.
+func (field StaticField[T]) Ptr() *T {
+	return field.ptr
+}
+
+// KeyValueBool acquires tag key value.
+// Returns ok(true) if key exists.
+func (field StaticField[T]) KeyValueBool(key string) (value string, ok bool) {
+	return Field{Tags: field.Tags}.KeyValueBool(key)
+}
+
+// TagByKey returns first tag with passed key.
+// Returns ok(true) if key exists.
+func (field StaticField[T]) TagByKey(key string) (tag Tag, ok bool) {
+	return Field{Tags: field.Tags}.TagByKey(key)
+}
+
+// KeyValues returns values of every tag with passed key, in tag order.
+func (field StaticField[T]) KeyValues(key string) []string {
+	return Field{Tags: field.Tags}.KeyValues(key)
+}
+
+// KeyValue returns tag key value.
+func (field StaticField[T]) KeyValue(key string) string {
+	value, _ := field.KeyValueBool(key)
+	return value
+}
+
+// HasKey checks if field contains tag key.
+func (field StaticField[T]) HasKey(key string) bool {
+	_, ok := field.KeyValueBool(key)
+	return ok
+}
+
+// Field returns reflection based Field, equal to the one returned by
+// TagSettings.ParseStruct.
+func (field StaticField[T]) Field() Field {
+	value := reflect.ValueOf(field.ptr).Elem()
+
+	return Field{
+		Value: value,
+		Name:  field.Name,
+		Path:  field.Name,
+		Kind:  value.Kind(),
+		Tags:  slices.Clone(field.Tags),
+	}
+}
@@ -0,0 +1,340 @@
+package gotags
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// structFieldInfo describes struct field reachable from some struct type.
+type structFieldInfo struct {
+	field reflect.StructField
+	index []int // Index sequence from the struct, like reflect.Value.FieldByIndex.
+}
+
+// structFields returns exported fields of typeOf in declaration order.
+// When recursive parsing is enabled, anonymous embedded structs which are
+// not tagged with TagSettings name are flattened and their fields promoted
+// using encoding/json rules: the shallowest field wins, fields on the same
+// depth conflict and are dropped unless exactly one of them is tagged.
+func (tg *TagSettings) structFields(typeOf reflect.Type) []structFieldInfo {
+	return collectStructFields(typeOf, tg.recursive, tg.isTagged)
+}
+
+// isTagged reports whether structField has tag with TagSettings name.
+func (tg *TagSettings) isTagged(structField reflect.StructField) bool {
+	_, ok := structField.Tag.Lookup(tg.Name)
+	return ok
+}
+
+// collectStructFields returns exported fields of typeOf, see
+// TagSettings.structFields. isTagged reports whether field is tagged with
+// parsed tag name(s).
+func collectStructFields(
+	typeOf reflect.Type,
+	recursive bool,
+	isTagged func(reflect.StructField) bool,
+) []structFieldInfo {
+	if !recursive {
+		fields := make([]structFieldInfo, 0, typeOf.NumField())
+
+		for i := 0; i < typeOf.NumField(); i++ {
+			structField := typeOf.Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+
+			fields = append(fields, structFieldInfo{
+				field: structField,
+				index: structField.Index,
+			})
+		}
+
+		return fields
+	}
+
+	return promotedStructFields(typeOf, isTagged)
+}
+
+func promotedStructFields(
+	typeOf reflect.Type,
+	isTagged func(reflect.StructField) bool,
+) []structFieldInfo {
+	type level struct {
+		typeOf reflect.Type
+		index  []int
+	}
+
+	current := []level{}
+	next := []level{{typeOf: typeOf}}
+	visited := map[reflect.Type]bool{}
+
+	// Fields grouped by name, each group holds only shallowest candidates.
+	candidates := map[string][]structFieldInfo{}
+	tagged := map[string]int{}
+	order := []string{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		depthNames := map[string]bool{}
+
+		for _, lvl := range current {
+			if visited[lvl.typeOf] {
+				continue
+			}
+			visited[lvl.typeOf] = true
+
+			for i := 0; i < lvl.typeOf.NumField(); i++ {
+				structField := lvl.typeOf.Field(i)
+				index := make([]int, len(lvl.index)+1)
+				copy(index, lvl.index)
+				index[len(lvl.index)] = i
+
+				if isPromotedEmbedded(structField, isTagged) {
+					embeddedType := structField.Type
+					if embeddedType.Kind() == reflect.Ptr {
+						embeddedType = embeddedType.Elem()
+					}
+
+					next = append(next, level{typeOf: embeddedType, index: index})
+					continue
+				}
+
+				if !structField.IsExported() {
+					continue
+				}
+
+				name := structField.Name
+				if _, ok := candidates[name]; ok && !depthNames[name] {
+					continue // Hidden by shallower field.
+				}
+				if !depthNames[name] {
+					order = append(order, name)
+				}
+
+				depthNames[name] = true
+				structField.Index = index
+				candidates[name] = append(candidates[name], structFieldInfo{
+					field: structField,
+					index: index,
+				})
+
+				if isTagged(structField) {
+					tagged[name]++
+				}
+			}
+		}
+	}
+
+	fields := make([]structFieldInfo, 0, len(order))
+
+	for _, name := range order {
+		group := candidates[name]
+		if len(group) == 1 {
+			fields = append(fields, group[0])
+			continue
+		}
+		if tagged[name] != 1 {
+			continue // Ambiguous, drop all like encoding/json.
+		}
+
+		for _, info := range group {
+			if isTagged(info.field) {
+				fields = append(fields, info)
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return indexLess(fields[i].index, fields[j].index)
+	})
+
+	return fields
+}
+
+// isPromotedEmbedded reports whether fields of anonymous structField should
+// be promoted into parent struct.
+func isPromotedEmbedded(
+	structField reflect.StructField,
+	isTagged func(reflect.StructField) bool,
+) bool {
+	if !structField.Anonymous || isTagged(structField) {
+		return false
+	}
+
+	embeddedType := structField.Type
+	if embeddedType.Kind() == reflect.Ptr {
+		embeddedType = embeddedType.Elem()
+	}
+
+	return embeddedType.Kind() == reflect.Struct
+}
+
+// fieldByIndex works like reflect.Value.FieldByIndex, but returns ok(false)
+// instead of panicking when embedded pointer is nil.
+func fieldByIndex(valueOf reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && valueOf.Kind() == reflect.Ptr {
+			if valueOf.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			valueOf = valueOf.Elem()
+		}
+
+		valueOf = valueOf.Field(fieldIndex)
+	}
+
+	return valueOf, true
+}
+
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// hasStructElements reports whether container typeOf (possibly nested)
+// holds structs or pointers to structs.
+func hasStructElements(typeOf reflect.Type) bool {
+	visited := map[reflect.Type]bool{}
+
+	for !visited[typeOf] {
+		visited[typeOf] = true
+
+		switch typeOf.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr:
+			typeOf = typeOf.Elem()
+		case reflect.Struct:
+			return true
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// sortedMapKeys returns map keys in stable order, so parsed fields have
+// deterministic order between calls.
+func sortedMapKeys(mapValue reflect.Value) []reflect.Value {
+	keys := mapValue.MapKeys()
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		default:
+			return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+		}
+	})
+
+	return keys
+}
+
+// formatMapKey formats map key for field path, string keys are quoted,
+// like `Limits["eu"]`.
+func formatMapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return strconv.Quote(key.String())
+	}
+
+	return fmt.Sprint(key.Interface())
+}
+
+// structVisitor parses struct valueOf, prefix is the struct path followed by
+// dot, like "Server.".
+type structVisitor func(valueOf reflect.Value, prefix string) error
+
+// walkChildValue descends into value which is struct, pointer to struct or
+// container (slice, array, map) of them and calls visit for every struct.
+// Nil pointers and pointers already being parsed are skipped.
+func walkChildValue(
+	value reflect.Value,
+	path string,
+	state *parseState,
+	visit structVisitor,
+) error {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return walkElements(value, path, state, visit)
+	case reflect.Ptr:
+		if value.IsNil() || value.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+
+		pointer := visitedPointer{address: value.Pointer(), typeOf: value.Type()}
+		if pointer == state.root || state.visited[pointer] {
+			return nil
+		}
+		if state.visited == nil {
+			state.visited = make(map[visitedPointer]bool)
+		}
+
+		state.visited[pointer] = true
+		defer delete(state.visited, pointer)
+
+		return visit(value.Elem(), path+".")
+	case reflect.Struct:
+		return visit(value, path+".")
+	default:
+		return nil
+	}
+}
+
+// walkElements walks every element of slice, array or map containerValue.
+// Element paths include index or map key.
+func walkElements(
+	containerValue reflect.Value,
+	path string,
+	state *parseState,
+	visit structVisitor,
+) error {
+	if !hasStructElements(containerValue.Type()) {
+		return nil
+	}
+
+	if containerValue.Kind() == reflect.Map {
+		for _, key := range sortedMapKeys(containerValue) {
+			err := walkChildValue(
+				containerValue.MapIndex(key),
+				path+"["+formatMapKey(key)+"]",
+				state,
+				visit,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for i := 0; i < containerValue.Len(); i++ {
+		err := walkChildValue(
+			containerValue.Index(i),
+			path+"["+strconv.Itoa(i)+"]",
+			state,
+			visit,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package gotags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func newTestConstraintSettings() *TagSettings {
+	return NewSettings("validator").
+		WithCollectAllErrors().
+		WithConstraints(
+			Excludes("gt", "eq"),
+			Requires("lt", "gt"),
+			ExactlyOneOf("email", "phone", "url").When("contact"),
+		).
+		AddKeys(
+			NewKey("gt", false, false, nil),
+			NewKey("lt", false, false, nil),
+			NewKey("eq", false, false, nil),
+			NewKey("email", true, false, nil),
+			NewKey("phone", true, false, nil),
+			NewKey("url", true, false, nil),
+			NewKey("contact", true, false, nil),
+		)
+}
+
+func Test_Constraints(t *testing.T) {
+	t.Run("Valid tags", func(t *testing.T) {
+		type testStruct struct {
+			Age     int    `validator:"gt:10;lt:20;email"`
+			Contact string `validator:"contact;phone"`
+			Other   string `validator:"eq:1"`
+		}
+
+		fields, err := newTestConstraintSettings().ParseStruct(&testStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 3, "unexpected field count")
+	})
+
+	t.Run("Violations name field and keys", func(t *testing.T) {
+		type testStruct struct {
+			Excluded int    `validator:"gt:10;eq:5;url"`
+			Requires int    `validator:"lt:10;phone"`
+			NoneOf   string `validator:"contact;eq:1"`
+			TwoOf    string `validator:"email;url"`
+		}
+
+		fields, err := newTestConstraintSettings().ParseStruct(&testStruct{})
+		testza.AssertErrorIs(t, err, ErrConstraintViolated, "expected constraint error")
+		testza.AssertLen(t, fields, 0, "expected no valid fields")
+
+		messages := []string{}
+		offsets := []int{}
+		for _, v := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var parseErr *ParseError
+			testza.AssertTrue(t, errors.As(v, &parseErr), "expected ParseError")
+			messages = append(messages, parseErr.Error())
+			offsets = append(offsets, parseErr.Offset)
+		}
+
+		testza.AssertEqual(t, messages, []string{
+			"field 'Excluded': tag keys 'gt', 'eq' are mutually exclusive",
+			"field 'Requires': tag key 'lt' requires 'gt'",
+			"field 'NoneOf': exactly one of tag keys 'email', 'phone', 'url' is required",
+			"field 'TwoOf': exactly one of tag keys 'email', 'phone', 'url' is allowed, " +
+				"found 'email', 'url'",
+		}, "unexpected messages")
+		testza.AssertEqual(t, offsets, []int{6, 0, -1, 6}, "unexpected offsets")
+	})
+	t.Run("Exactly one of without trigger", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithConstraints(
+				Excludes("gt", "eq"),
+				ExactlyOneOf("email", "phone", "url"),
+			).
+			AddKeys(
+				NewKey("gt", false, false, nil),
+				NewKey("eq", false, false, nil),
+				NewKey("email", true, false, nil),
+				NewKey("phone", true, false, nil),
+				NewKey("url", true, false, nil),
+			)
+
+		type mixedStruct struct {
+			Age     int    `validator:"gt:10"`
+			Email   string `validator:"email"`
+			Website string `validator:"url"`
+		}
+
+		fields, err := settings.ParseStruct(&mixedStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 3, "unexpected field count")
+
+		_, err = settings.ParseStructTag(`validator:"email;url"`)
+		testza.AssertErrorIs(t, err, ErrConstraintViolated, "expected constraint error")
+	})
+}
@@ -0,0 +1,231 @@
+package gotags
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var errorKeys = []Key{
+	NewKey("required", true, false, nil),
+	NewKey("gt", false, false, nil).WithType(ValueInt),
+	NewKey("lt", false, false, nil),
+}
+
+func Test_ParseError(t *testing.T) {
+	parseError := func(t *testing.T, tagSettings *TagSettings, data any) *ParseError {
+		t.Helper()
+
+		_, err := tagSettings.ParseStruct(data)
+		testza.AssertNotNil(t, err, "expected error")
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		return parseErr
+	}
+
+	t.Run("Unknown key", func(t *testing.T) {
+		type server struct {
+			Host string `testtag:"required;eq:x"`
+		}
+		data := struct {
+			Server server
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			WithRecursiveParsing().
+			AddKeys(errorKeys...)
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrUnknownKey), "unexpected kind")
+		testza.AssertEqual(t, parseErr.Type, reflect.TypeOf(server{}), "unexpected type")
+		testza.AssertEqual(t, parseErr.Field, "Server.Host", "unexpected field")
+		testza.AssertEqual(t, parseErr.Tag, "testtag", "unexpected tag")
+		testza.AssertEqual(t, parseErr.Key, "eq", "unexpected key")
+		testza.AssertEqual(t, parseErr.Raw, "required;eq:x", "unexpected raw")
+		testza.AssertEqual(t, parseErr.Offset, 9, "unexpected offset")
+		testza.AssertEqual(t, parseErr.Error(),
+			"field 'Server.Host': tag 'eq' does not exist", "unexpected message")
+	})
+
+	t.Run("Missing argument", func(t *testing.T) {
+		data := struct {
+			Age int `testtag:"required;lt"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			WithRecursiveParsing().
+			AddKeys(errorKeys...)
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrMissingArgument), "unexpected kind")
+		testza.AssertEqual(t, parseErr.Key, "lt", "unexpected key")
+		testza.AssertEqual(t, parseErr.Offset, 11, "unexpected offset")
+	})
+
+	t.Run("Unexpected argument", func(t *testing.T) {
+		data := struct {
+			Age int `testtag:"gt:1;required:yes"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			WithRecursiveParsing().
+			AddKeys(errorKeys...)
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrUnexpectedArgument),
+			"unexpected kind")
+		testza.AssertEqual(t, parseErr.Offset, 14, "unexpected offset")
+	})
+
+	t.Run("Invalid value with escapes before it", func(t *testing.T) {
+		data := struct {
+			Age int `testtag:"lt:a\\;b;gt:ten"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			WithRecursiveParsing().
+			AddKeys(errorKeys...)
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrInvalidValue), "unexpected kind")
+		testza.AssertEqual(t, parseErr.Key, "gt", "unexpected key")
+		testza.AssertEqual(t, parseErr.Offset, 11, "unexpected offset")
+	})
+
+	t.Run("Required key missing", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			WithRecursiveParsing().
+			AddKeys(errorKeys...).
+			AddKey(NewKey("name", false, true, nil))
+		data := struct {
+			Name string `testtag:"required"`
+		}{}
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrRequiredKeyMissing),
+			"unexpected kind")
+		testza.AssertEqual(t, parseErr.Key, "name", "unexpected key")
+		testza.AssertEqual(t, parseErr.Offset, -1, "unexpected offset")
+	})
+
+	t.Run("Trailing escape", func(t *testing.T) {
+		data := struct {
+			Name string `testtag:"lt:abc\\"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			WithRecursiveParsing().
+			AddKeys(errorKeys...)
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrTrailingEscape), "unexpected kind")
+		testza.AssertEqual(t, parseErr.Offset, 6, "unexpected offset")
+	})
+
+	t.Run("Validator error is wrapped", func(t *testing.T) {
+		errCustom := errors.New("custom")
+		tagSettings := NewSettings("testtag").
+			AddKey(NewKey("lt", false, false, func(string) error { return errCustom }))
+		data := struct {
+			Name string `testtag:"lt:1"`
+		}{}
+
+		parseErr := parseError(t, tagSettings, &data)
+		testza.AssertTrue(t, errors.Is(parseErr, ErrInvalidValue), "unexpected kind")
+		testza.AssertTrue(t, errors.Is(parseErr, errCustom), "expected wrapped error")
+	})
+}
+
+func Test_CollectAllErrors(t *testing.T) {
+	type collectStruct struct {
+		Name    string `testtag:"required;eq:x"`
+		Age     int    `testtag:"gt:ten;lt"`
+		Country string `testtag:"required"`
+		Phone   string `testtag:"unknown"`
+	}
+
+	t.Run("All problems are returned", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithCollectAllErrors().
+			AddKeys(errorKeys...)
+
+		fields, err := tagSettings.ParseStruct(&collectStruct{})
+		testza.AssertNotNil(t, err, "expected error")
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		testza.AssertTrue(t, ok, "expected joined errors")
+
+		errs := joined.Unwrap()
+		testza.AssertLen(t, errs, 4, "unexpected errors len")
+
+		expected := []struct {
+			Field string
+			Key   string
+			Kind  error
+		}{
+			{"Name", "eq", ErrUnknownKey},
+			{"Age", "gt", ErrInvalidValue},
+			{"Age", "lt", ErrMissingArgument},
+			{"Phone", "unknown", ErrUnknownKey},
+		}
+
+		for idx, v := range expected {
+			var parseErr *ParseError
+			testza.AssertTrue(t, errors.As(errs[idx], &parseErr),
+				"expected ParseError")
+			testza.AssertEqual(t, parseErr.Field, v.Field, "unexpected field")
+			testza.AssertEqual(t, parseErr.Key, v.Key, "unexpected key")
+			testza.AssertTrue(t, errors.Is(parseErr, v.Kind), "unexpected kind")
+		}
+
+		testza.AssertEqual(t, testPaths(fields), []string{"Country"},
+			"unexpected clean fields")
+	})
+
+	t.Run("Every processor runs", func(t *testing.T) {
+		data := struct {
+			Name    string `testtag:"required"`
+			Country string `testtag:"required"`
+		}{}
+
+		calls := 0
+		tagSettings := NewSettings("testtag").
+			WithCollectAllErrors().
+			WithProcessor(func(field Field) error {
+				calls++
+				return errors.New("processor error")
+			}).
+			AddKeys(errorKeys...)
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNotNil(t, err, "expected error")
+		testza.AssertEqual(t, calls, 2, "unexpected processor calls")
+		testza.AssertLen(t, fields, 2, "unexpected fields len")
+		testza.AssertTrue(t, errors.Is(err, ErrProcessorFailed), "unexpected kind")
+		testza.AssertContains(t, err.Error(), "field 'Country': processor error",
+			"unexpected message")
+	})
+
+	t.Run("No errors", func(t *testing.T) {
+		data := struct {
+			Name string `testtag:"required"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithCollectAllErrors().
+			AddKeys(errorKeys...)
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 1, "unexpected fields len")
+	})
+}
@@ -0,0 +1,97 @@
+package gotags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type testRefServer struct {
+	Port    int `validator:"min:1"`
+	Timeout time.Duration
+}
+
+type testRefStruct struct {
+	Type      string
+	StartDate time.Time
+	EndDate   time.Time `validator:"gtfield:StartDate"`
+	Admin     string    `validator:"requiredIf:Type=admin"`
+	Proxy     int       `validator:"ltfield:Server.Port"`
+	Server    *testRefServer
+}
+
+func newTestRefSettings() *TagSettings {
+	return NewSettings("validator").
+		WithCollectAllErrors().
+		AddKeys(
+			NewKey("gtfield", false, false, nil).WithFieldRef(""),
+			NewKey("ltfield", false, false, nil).WithFieldRef(""),
+			NewKey("requiredIf", false, false, nil).WithFieldRef("="),
+			NewKey("min", false, false, nil),
+		)
+}
+
+func Test_FieldRef(t *testing.T) {
+	t.Run("Referenced field is returned", func(t *testing.T) {
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		data := &testRefStruct{
+			Type:      "admin",
+			StartDate: start,
+			Server:    &testRefServer{Port: 8080},
+		}
+
+		fields, err := newTestRefSettings().ParseStruct(data)
+		testza.AssertNoError(t, err, "unexpected error")
+
+		startField, err := fields[0].RefField("gtfield")
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, startField.Path, "StartDate", "unexpected path")
+		testza.AssertEqual(t, startField.Value.Interface(), start, "unexpected value")
+
+		tag, _ := fields[1].TagByKey("requiredIf")
+		path, rest := tag.FieldRef()
+		testza.AssertEqual(t, path, "Type", "unexpected ref path")
+		testza.AssertEqual(t, rest, "admin", "unexpected ref rest")
+
+		typeField, err := fields[1].RefField("requiredIf")
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, typeField.Value.String(), rest, "unexpected value")
+
+		portField, err := fields[2].RefField("ltfield")
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, portField.Path, "Server.Port", "unexpected path")
+		testza.AssertEqual(t, portField.Value.Int(), int64(8080), "unexpected value")
+		testza.AssertEqual(t, portField.Tags, []Tag{{Key: "min", Value: "1"}},
+			"unexpected tags")
+	})
+
+	t.Run("Nil pointer on path", func(t *testing.T) {
+		fields, err := newTestRefSettings().ParseStruct(&testRefStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+
+		_, err = fields[2].RefField("ltfield")
+		testza.AssertNotNil(t, err, "expected error")
+
+		_, err = fields[2].RefField("gtfield")
+		testza.AssertNotNil(t, err, "expected error")
+	})
+
+	t.Run("Unknown fields are rejected", func(t *testing.T) {
+		type testStruct struct {
+			Start  int
+			End    int `validator:"gtfield:Begin"`
+			Server testRefServer
+			Proxy  int `validator:"ltfield:Server.Host"`
+			Empty  int `validator:"requiredIf:=admin"`
+		}
+
+		_, err := newTestRefSettings().ParseStruct(&testStruct{})
+		testza.AssertErrorIs(t, err, ErrInvalidFieldRef, "expected field ref error")
+		testza.AssertErrorIs(t, err, ErrInvalidValue, "expected invalid value")
+		testza.AssertEqual(t, err.Error(), "field 'End': tag 'gtfield' references unknown field 'Begin'\n"+
+			"field 'Proxy': tag 'ltfield' references unknown field 'Server.Host'\n"+
+			"field 'Empty': tag 'requiredIf' requires field reference value, got '=admin'",
+			"unexpected errors")
+	})
+}
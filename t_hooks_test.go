@@ -0,0 +1,167 @@
+package gotags
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type hookEmail string
+
+func (email *hookEmail) ProcessTagField(field Field) error {
+	if !strings.Contains(string(*email), "@") {
+		return errors.New("invalid email")
+	}
+
+	*email = hookEmail(strings.ToLower(string(*email)))
+	return nil
+}
+
+type hookServer struct {
+	Host  string `validator:"required"`
+	Port  int    `validator:"required"`
+	calls *[]string
+}
+
+func (server *hookServer) BeforeTagParse() error {
+	*server.calls = append(*server.calls, "server before")
+	return nil
+}
+
+func (server *hookServer) AfterTagParse(fields []Field) error {
+	*server.calls = append(*server.calls, "server after "+hookFieldPaths(fields))
+
+	if server.Port == 0 {
+		return errors.New("port is not set")
+	}
+
+	return nil
+}
+
+type hookConfig struct {
+	Name   string    `validator:"required"`
+	Email  hookEmail `validator:"required"`
+	Server hookServer
+	calls  *[]string
+}
+
+func (config *hookConfig) BeforeTagParse() error {
+	*config.calls = append(*config.calls, "config before")
+	config.Server.calls = config.calls
+	return nil
+}
+
+func (config *hookConfig) AfterTagParse(fields []Field) error {
+	*config.calls = append(*config.calls, "config after "+hookFieldPaths(fields))
+	return nil
+}
+
+func hookFieldPaths(fields []Field) string {
+	names := make([]string, len(fields))
+	for idx, field := range fields {
+		names[idx] = field.Path
+	}
+
+	return strings.Join(names, ",")
+}
+
+func Test_Hooks(t *testing.T) {
+	newConfig := func(calls *[]string) *hookConfig {
+		return &hookConfig{
+			Name:   "app",
+			Email:  "Admin@Example.com",
+			Server: hookServer{Host: "localhost", Port: 80},
+			calls:  calls,
+		}
+	}
+
+	t.Run("Hooks are called in order", func(t *testing.T) {
+		var calls []string
+		settings := NewSettings("validator").
+			WithRecursiveParsing().
+			WithStructProcessor(func(root reflect.Value, fields []Field) error {
+				calls = append(calls, "struct processor")
+				return nil
+			}).
+			AddKey(NewKey("required", true, false, nil))
+
+		config := newConfig(&calls)
+
+		_, err := settings.ParseStruct(config)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, calls, []string{
+			"config before",
+			"server before",
+			"server after Server.Host,Server.Port",
+			"config after Name,Email,Server.Host,Server.Port",
+			"struct processor",
+		}, "unexpected calls")
+		testza.AssertEqual(t, config.Email, hookEmail("admin@example.com"),
+			"field hook must update field")
+	})
+
+	t.Run("Field hook error is returned", func(t *testing.T) {
+		var calls []string
+		config := newConfig(&calls)
+		config.Email = "admin"
+
+		settings := NewSettings("validator").
+			WithRecursiveParsing().
+			AddKey(NewKey("required", true, false, nil))
+
+		_, err := settings.ParseStruct(config)
+		testza.AssertEqual(t, err.Error(), "invalid email", "unexpected error")
+	})
+
+	t.Run("Hook errors are collected", func(t *testing.T) {
+		var calls []string
+		config := newConfig(&calls)
+		config.Email = "admin"
+		config.Server.Port = 0
+
+		settings := NewSettings("validator").
+			WithRecursiveParsing().
+			WithCollectAllErrors().
+			AddKey(NewKey("required", true, false, nil))
+
+		fields, err := settings.ParseStruct(config)
+		testza.AssertErrorIs(t, err, ErrProcessorFailed, "expected processor error")
+		testza.AssertLen(t, fields, 4, "expected parsed fields")
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		testza.AssertTrue(t, ok, "expected joined errors")
+		testza.AssertLen(t, joined.Unwrap(), 2, "unexpected errors len")
+
+		var fieldErr *ParseError
+		testza.AssertTrue(t, errors.As(joined.Unwrap()[0], &fieldErr), "expected ParseError")
+		testza.AssertEqual(t, fieldErr.Field, "Email", "unexpected field")
+
+		var structErr *ParseError
+		testza.AssertTrue(t, errors.As(joined.Unwrap()[1], &structErr), "expected ParseError")
+		testza.AssertEqual(t, structErr.Type, reflect.TypeOf(hookServer{}), "unexpected type")
+		testza.AssertEqual(t, structErr.Field, "Server", "unexpected field")
+		testza.AssertEqual(t, structErr.Error(), "field 'Server': port is not set",
+			"unexpected message")
+	})
+
+	t.Run("Tag set calls hooks once", func(t *testing.T) {
+		var calls []string
+		tagSet := NewTagSet(
+			NewSettings("validator").
+				WithRecursiveParsing().
+				AddKey(NewKey("required", true, false, nil)),
+			NewSettings("db").WithNoKeyExistValidation(),
+		)
+
+		config := newConfig(&calls)
+
+		_, err := tagSet.ParseStruct(config)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, calls, 4, "unexpected calls")
+		testza.AssertEqual(t, config.Email, hookEmail("admin@example.com"),
+			"field hook must update field")
+	})
+}
@@ -0,0 +1,110 @@
+package gotags
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var diveSettings = NewSettings("dive").
+	WithCustomSeparators(",", "=").
+	WithEscapeCharacter('\\').
+	AddKeys(
+		NewKey("min", false, false, nil).WithType(ValueInt),
+		NewKey("max", false, false, nil).WithType(ValueInt),
+	)
+
+var nestedKeys = []Key{
+	NewKey("dive", false, false, nil).WithTagSettings(diveSettings),
+	NewKey("each", false, false, nil).WithTagSettings(
+		NewSettings("each").AddKeys(
+			NewKey("required", true, false, nil),
+			NewKey("gt", false, false, nil).WithType(ValueInt),
+			NewKey("dive", false, false, nil).WithTagSettings(diveSettings),
+		),
+	),
+	NewKey("required", true, false, nil),
+}
+
+func Test_NestedTags(t *testing.T) {
+	t.Run("Nested tags are parsed", func(t *testing.T) {
+		data := struct {
+			Items  []int   `validator:"dive:min=1,max=5;required"`
+			Groups [][]int `validator:"each:required\\;gt:3\\;dive:min=2"`
+		}{}
+
+		tagSettings := NewSettings("validator").
+			WithEscapeCharacter('\\').
+			AddKeys(nestedKeys...)
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 2, "unexpected fields len")
+
+		dive, _ := fields[0].TagByKey("dive")
+		testza.AssertLen(t, dive.Tags(), 2, "unexpected nested tags len")
+		testza.AssertEqual(t, dive.Tags()[0].Key, "min", "unexpected nested key")
+		testza.AssertEqual(t, dive.Tags()[1].Int(), 5, "unexpected nested value")
+
+		each := fields[1].FirstTag().Tags()
+		testza.AssertLen(t, each, 3, "unexpected nested tags len")
+		testza.AssertEqual(t, each[1].Int(), 3, "unexpected nested value")
+		testza.AssertEqual(t, each[2].Tags()[0].Int(), 2,
+			"unexpected deeper nested value")
+	})
+
+	t.Run("Nested problems are parse errors", func(t *testing.T) {
+		tagSettings := NewSettings("validator").
+			WithEscapeCharacter('\\').
+			AddKeys(nestedKeys...)
+
+		for _, tc := range []struct {
+			tag  string
+			kind error
+		}{
+			{tag: "dive:min=1,mx=5", kind: ErrUnknownKey},
+			{tag: "dive:min=a", kind: ErrInvalidValue},
+			{tag: "each:gt", kind: ErrMissingArgument},
+			{tag: "each:dive:max", kind: ErrMissingArgument},
+		} {
+			_, err := tagSettings.ParseStructTag(
+				reflect.StructTag(`validator:"` + tc.tag + `"`))
+
+			var parseErr *ParseError
+			testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+			testza.AssertEqual(t, parseErr.Kind, ErrInvalidValue,
+				"unexpected error kind of "+tc.tag)
+			testza.AssertErrorIs(t, err, tc.kind, "unexpected nested kind of "+tc.tag)
+		}
+	})
+
+	t.Run("Child settings name is not used", func(t *testing.T) {
+		for _, name := range []string{"", "my each", `a:"b"`} {
+			child := NewSettings(name).
+				WithCustomSeparators(",", "=").
+				AddKey(NewKey("min", false, false, nil))
+
+			settings := NewSettings("validator").
+				AddKey(NewKey("each", false, false, nil).WithTagSettings(child))
+
+			tags, err := settings.ParseStructTag(`validator:"each:min=1"`)
+			testza.AssertNoError(t, err, "unexpected error for "+name)
+			testza.AssertLen(t, tags[0].Tags(), 1, "unexpected nested tags for "+name)
+
+			_, err = settings.ParseStructTag(`validator:"each:min=1,max=5"`)
+			testza.AssertErrorIs(t, err, ErrUnknownKey, "expected unknown key for "+name)
+		}
+	})
+
+	t.Run("Nil settings are rejected", func(t *testing.T) {
+		testza.AssertPanics(t, func() {
+			NewKey("each", false, false, nil).WithTagSettings(nil)
+		}, "expected panic")
+	})
+
+	t.Run("Not nested tag", func(t *testing.T) {
+		testza.AssertNil(t, Tag{Key: "eq", Value: "a"}.Tags(), "expected no tags")
+	})
+}
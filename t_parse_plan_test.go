@@ -0,0 +1,244 @@
+package gotags
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func Test_StructPlanCache(t *testing.T) {
+	type planStruct struct {
+		Name string `testtag:"required"`
+		Age  int    `testtag:"min=10"`
+	}
+
+	t.Run("Plan is compiled once per type", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			AddKeys(
+				NewKey("required", true, false, nil),
+				NewKey("min", false, false, nil),
+			)
+
+		first, err := tagSettings.ParseStruct(&planStruct{Name: "a"})
+		testza.AssertNoError(t, err, "unexpected error")
+
+		second, err := tagSettings.ParseStruct(&planStruct{Name: "b"})
+		testza.AssertNoError(t, err, "unexpected error")
+
+		typeOf := reflect.TypeOf(planStruct{})
+		testza.AssertTrue(t, tagSettings.structPlan(typeOf) == tagSettings.structPlan(typeOf),
+			"expected cached plan")
+		testza.AssertEqual(t, first[0].Tags, second[0].Tags, "unexpected tags")
+		testza.AssertEqual(t, second[0].Value.String(), "b",
+			"unexpected field value")
+	})
+
+	t.Run("Returned tags are copies", func(t *testing.T) {
+		var seen []string
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			WithProcessor(func(field Field) error {
+				seen = append(seen, field.Tags[0].Value)
+				field.Tags[0].Value = "MUTATED"
+				return nil
+			}).
+			AddKeys(
+				NewKey("required", true, false, nil),
+				NewKey("min", false, false, nil),
+			)
+
+		for i := 0; i < 2; i++ {
+			fields, err := tagSettings.ParseStruct(&planStruct{})
+			testza.AssertNoError(t, err, "unexpected error")
+			testza.AssertEqual(t, fields[1].KeyValue("min"), "MUTATED",
+				"processor must change returned tags")
+		}
+
+		testza.AssertEqual(t, seen, []string{"", "10", "", "10"},
+			"later calls must not see changed tags")
+
+		tagSet := NewTagSet(tagSettings)
+
+		fields, err := tagSet.ParseStruct(&planStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, fields[1].KeyValue("min"), "10",
+			"tag set tags must not be changed by namespace processor")
+	})
+
+	t.Run("Returned typed values are copies", func(t *testing.T) {
+		type listStruct struct {
+			Items string `testtag:"list=a|b,map=a:1,call=f(a),each=min:1"`
+		}
+
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			AddKeys(
+				NewKey("list", false, false, nil).WithStringList("|"),
+				NewKey("map", false, false, nil).WithMap("|", ":"),
+				NewKey("call", false, false, nil).WithCall("|"),
+				NewKey("each", false, false, nil).WithTagSettings(
+					NewSettings("each").
+						WithCustomSeparators("|", ":").
+						AddKey(NewKey("min", false, false, nil)),
+				),
+			)
+
+		for i := 0; i < 2; i++ {
+			fields, err := tagSettings.ParseStruct(&listStruct{})
+			testza.AssertNoError(t, err, "unexpected error")
+
+			list, _ := fields[0].TagByKey("list")
+			testza.AssertEqual(t, list.Strings(), []string{"a", "b"}, "unexpected list")
+			testza.AssertEqual(t, list.Values(), []string{"a", "b"}, "unexpected values")
+			list.Strings()[0] = "MUT"
+			list.Values()[0] = "MUT"
+
+			mapTag, _ := fields[0].TagByKey("map")
+			testza.AssertEqual(t, mapTag.Map(), map[string]string{"a": "1"}, "unexpected map")
+			mapTag.Map()["a"] = "MUT"
+
+			call, _ := fields[0].TagByKey("call")
+			_, args := call.Call()
+			testza.AssertEqual(t, args, []string{"a"}, "unexpected call args")
+			args[0] = "MUT"
+
+			each, _ := fields[0].TagByKey("each")
+			testza.AssertEqual(t, each.Tags()[0].Value, "1", "unexpected nested value")
+			each.Tags()[0].Value = "MUT"
+		}
+	})
+
+	t.Run("Changing settings drops compiled plans", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			AddKeys(NewKey("required", true, false, nil))
+
+		_, err := tagSettings.ParseStruct(&planStruct{})
+		testza.AssertNotNil(t, err, "expected unknown key error")
+
+		tagSettings.AddKey(NewKey("min", false, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&planStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 2, "unexpected fields len")
+	})
+
+	t.Run("ResetCache drops compiled plans", func(t *testing.T) {
+		type untaggedStruct struct {
+			Name string `testtag:"required"`
+			Age  int
+		}
+
+		type lengthStruct struct {
+			Name string `testtag:"required=abc"`
+		}
+
+		maxLen := func(size int) Validator {
+			return func(value string) error {
+				if len(value) > size {
+					return errors.New("value too long")
+				}
+				return nil
+			}
+		}
+
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			AddKeys(NewKey("required", false, false, maxLen(5)))
+
+		_, err := tagSettings.ParseStruct(&lengthStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+
+		tagSettings.Keys[0].Validator = maxLen(1)
+
+		_, err = tagSettings.ParseStruct(&lengthStruct{})
+		testza.AssertNoError(t, err, "exported fields must not be checked again")
+
+		tagSettings.ResetCache()
+
+		_, err = tagSettings.ParseStruct(&lengthStruct{})
+		testza.AssertErrorIs(t, err, ErrInvalidValue, "changed validator expected")
+
+		tagSettings.Keys[0].IsBool = true
+		tagSettings.IncludeNotTagged = true
+		tagSettings.ResetCache()
+
+		fields, err := tagSettings.ParseStruct(&untaggedStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 2, "untagged field expected")
+	})
+
+	t.Run("Copies do not share plans", func(t *testing.T) {
+		first := NewTagSettings("testtag", ",", "=", nil, false,
+			NewKey("required", true, false, nil),
+			NewKey("min", false, false, nil),
+		)
+		second := first
+		second.Keys = second.Keys[:1]
+
+		_, err := first.ParseStruct(&planStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+
+		_, err = second.ParseStruct(&planStruct{})
+		testza.AssertErrorIs(t, err, ErrUnknownKey, "expected unknown key error")
+
+		_, err = first.ParseStruct(&planStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+	})
+
+	t.Run("Validation error is cached", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			AddKeys(NewKey("required", true, false, nil))
+
+		for i := 0; i < 2; i++ {
+			fields, err := tagSettings.ParseStruct(&planStruct{})
+			testza.AssertNotNil(t, err, "expected error")
+			testza.AssertNil(t, fields, "fields expected as nil")
+		}
+	})
+
+	t.Run("Settings without cache still parse", func(t *testing.T) {
+		tagSettings := TagSettings{
+			Name:      "testtag",
+			Separator: ",",
+			Equals:    "=",
+			Keys: []Key{
+				NewKey("required", true, false, nil),
+				NewKey("min", false, false, nil),
+			},
+		}
+
+		fields, err := tagSettings.ParseStruct(&planStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 2, "unexpected fields len")
+	})
+
+	t.Run("Concurrent parsing", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithCustomSeparators(",", "=").
+			AddKeys(
+				NewKey("required", true, false, nil),
+				NewKey("min", false, false, nil),
+			)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				fields, err := tagSettings.ParseStruct(&planStruct{})
+				testza.AssertNoError(t, err, "unexpected error")
+				testza.AssertLen(t, fields, 2, "unexpected fields len")
+			}()
+		}
+
+		wg.Wait()
+	})
+}
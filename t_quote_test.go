@@ -0,0 +1,133 @@
+package gotags
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var quotedKeys = []Key{
+	NewKey("required", true, false, nil),
+	NewKey("regex", false, false, nil),
+	NewKey("eq", false, false, nil),
+}
+
+func Test_QuotedValues(t *testing.T) {
+	parse := func(tg *TagSettings, tag string) ([]Tag, error) {
+		return tg.ParseStructTag(reflect.StructTag(`validator:"` + tag + `"`))
+	}
+
+	t.Run("Separators inside quotes are literal", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			AddKeys(quotedKeys...)
+
+		tags, err := parse(settings, `regex:'^a;b:c$';required`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tags, []Tag{
+			{Key: "regex", Value: "^a;b:c$"},
+			{Key: "required"},
+		}, "unexpected tags")
+	})
+
+	t.Run("Doubled quote is literal quote", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			AddKeys(quotedKeys...)
+
+		tags, err := parse(settings, `eq:'it''s;ok'`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tags, []Tag{{Key: "eq", Value: "it's;ok"}},
+			"unexpected tags")
+	})
+
+	t.Run("Quote inside unquoted value is literal", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			AddKeys(quotedKeys...)
+
+		tags, err := parse(settings, `eq:it's;required`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tags, []Tag{
+			{Key: "eq", Value: "it's"},
+			{Key: "required"},
+		}, "unexpected tags")
+	})
+
+	t.Run("Escapes inside quotes are literal", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			WithEscapeCharacter('\\').
+			AddKeys(quotedKeys...)
+
+		// Struct tag values are Go strings, backslashes are doubled.
+		tags, err := parse(settings, `regex:'^\\d+;\\w$';eq:a\\;b`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tags, []Tag{
+			{Key: "regex", Value: `^\d+;\w$`},
+			{Key: "eq", Value: "a;b"},
+		}, "unexpected tags")
+	})
+
+	t.Run("Custom separators", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			WithCustomSeparators(",", "=").
+			AddKeys(quotedKeys...)
+
+		tags, err := parse(settings, `required,regex='^a,b=c$',eq=1`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tags, []Tag{
+			{Key: "required"},
+			{Key: "regex", Value: "^a,b=c$"},
+			{Key: "eq", Value: "1"},
+		}, "unexpected tags")
+	})
+
+	t.Run("Quotes are literal by default", func(t *testing.T) {
+		tags, err := parse(NewSettings("validator").WithNoKeyExistValidation(),
+			`eq:'a;b'`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tags, []Tag{
+			{Key: "eq", Value: "'a"},
+			{Key: "b'"},
+		}, "unexpected tags")
+	})
+
+	t.Run("Unterminated quote returns error", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			AddKeys(quotedKeys...)
+
+		_, err := parse(settings, `required;regex:'^a;b$`)
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		testza.AssertErrorIs(t, err, ErrUnterminatedQuote, "unexpected error kind")
+		testza.AssertEqual(t, parseErr.Offset, 15, "unexpected offset")
+	})
+
+	t.Run("Text after closing quote returns error", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			AddKeys(quotedKeys...)
+
+		_, err := parse(settings, `regex:'a'b;required`)
+		testza.AssertErrorIs(t, err, ErrInvalidValue, "unexpected error kind")
+	})
+
+	t.Run("Error offset skips quoted separators", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithQuoteCharacter('\'').
+			AddKeys(quotedKeys...)
+
+		_, err := parse(settings, `regex:'a;b';lt:1`)
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		testza.AssertErrorIs(t, err, ErrUnknownKey, "unexpected error kind")
+		testza.AssertEqual(t, parseErr.Offset, 12, "unexpected offset")
+	})
+}
@@ -0,0 +1,52 @@
+package gotags
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type testStaticStruct struct {
+	Name string `validator:"required"`
+	Age  uint   `validator:"gt:10"`
+}
+
+func Test_StaticField(t *testing.T) {
+	settings := NewSettings("validator").AddKeys(
+		NewKey("required", true, false, nil),
+		NewKey("gt", false, false, nil),
+	)
+
+	t.Run("Field matches ParseStruct", func(t *testing.T) {
+		data := &testStaticStruct{Name: "John", Age: 30}
+
+		fields, err := settings.ParseStruct(data)
+		testza.AssertNoError(t, err, "unexpected error")
+
+		static := NewStaticField("Age", &data.Age, fields[1].Tags).Field()
+		testza.AssertEqual(t, static.Name, fields[1].Name, "unexpected name")
+		testza.AssertEqual(t, static.Path, fields[1].Path, "unexpected path")
+		testza.AssertEqual(t, static.Kind, fields[1].Kind, "unexpected kind")
+		testza.AssertEqual(t, static.Tags, fields[1].Tags, "unexpected tags")
+		testza.AssertEqual(t, static.Value.Addr().Pointer(),
+			fields[1].Value.Addr().Pointer(), "unexpected value")
+	})
+
+	t.Run("Typed getters and setters", func(t *testing.T) {
+		data := &testStaticStruct{Name: "John", Age: 30}
+		name := NewStaticField("Name", &data.Name, []Tag{{Key: "required"}})
+		age := NewStaticField("Age", &data.Age, []Tag{{Key: "gt", Value: "10"}})
+
+		testza.AssertEqual(t, age.Get(), uint(30), "unexpected value")
+
+		age.Set(40)
+		testza.AssertEqual(t, data.Age, uint(40), "value not set")
+
+		testza.AssertTrue(t, name.HasKey("required"), "expected key")
+		testza.AssertEqual(t, age.KeyValue("gt"), "10", "unexpected key value")
+
+		tag, ok := age.TagByKey("gt")
+		testza.AssertTrue(t, ok, "expected tag")
+		testza.AssertEqual(t, tag.Int(), 10, "unexpected typed value")
+	})
+}
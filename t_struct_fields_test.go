@@ -0,0 +1,366 @@
+package gotags
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type testTLSConfig struct {
+	CertFile string `testtag:"required"`
+	KeyFile  string `testtag:"required"`
+}
+
+type testServerConfig struct {
+	Host string `testtag:"required"`
+	TLS  *testTLSConfig
+}
+
+type testBaseConfig struct {
+	ID   string `testtag:"required"`
+	Name string `testtag:"required"`
+}
+
+type TestTaggedBaseConfig struct {
+	ID string `testtag:"required"`
+}
+
+type testOtherBaseConfig struct {
+	Name string `testtag:"required"`
+}
+
+type testNestedConfig struct {
+	testBaseConfig
+	Server testServerConfig
+	Note   string `testtag:"required"`
+}
+
+func testPaths(fields []Field) []string {
+	paths := make([]string, len(fields))
+	for i, field := range fields {
+		paths[i] = field.Path
+	}
+	return paths
+}
+
+func Test_ParseStructRecursive(t *testing.T) {
+	t.Run("Not recursive by default", func(t *testing.T) {
+		data := testNestedConfig{}
+
+		fields, err := NewSettings("testtag").
+			AddKeys(NewKey("required", true, false, nil)).
+			ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{"Note"},
+			"unexpected field paths")
+	})
+
+	t.Run("Nested and embedded structs", func(t *testing.T) {
+		data := testNestedConfig{
+			testBaseConfig: testBaseConfig{ID: "1"},
+			Server: testServerConfig{
+				Host: "localhost",
+				TLS:  &testTLSConfig{CertFile: "cert.pem"},
+			},
+		}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{
+			"ID",
+			"Name",
+			"Server.Host",
+			"Server.TLS.CertFile",
+			"Server.TLS.KeyFile",
+			"Note",
+		}, "unexpected field paths")
+
+		testza.AssertEqual(t, fields[0].Value.String(), "1", "unexpected value")
+		testza.AssertEqual(t, fields[3].Name, "CertFile", "unexpected name")
+		testza.AssertEqual(t, fields[3].Value.String(), "cert.pem",
+			"unexpected value")
+	})
+
+	t.Run("Nil pointer is skipped", func(t *testing.T) {
+		data := testNestedConfig{}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{
+			"ID", "Name", "Server.Host", "Note",
+		}, "unexpected field paths")
+	})
+
+	t.Run("Nested values can be changed", func(t *testing.T) {
+		data := testNestedConfig{Server: testServerConfig{TLS: &testTLSConfig{}}}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+
+		for _, field := range fields {
+			if field.Path == "Server.TLS.KeyFile" {
+				testza.AssertNoError(t, field.SetValue("key.pem"))
+			}
+		}
+
+		testza.AssertEqual(t, data.Server.TLS.KeyFile, "key.pem",
+			"unexpected value")
+	})
+
+	t.Run("Shallow field hides promoted field", func(t *testing.T) {
+		data := struct {
+			testBaseConfig
+			Name string `testtag:"required"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{"ID", "Name"},
+			"unexpected field paths")
+		testza.AssertEqual(t, fields[1].Value.String(), "",
+			"unexpected value")
+	})
+
+	t.Run("Conflicting promoted fields are dropped", func(t *testing.T) {
+		data := struct {
+			testBaseConfig
+			testOtherBaseConfig
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{"ID"},
+			"unexpected field paths")
+	})
+
+	t.Run("Tagged embedded struct is not promoted", func(t *testing.T) {
+		data := struct {
+			TestTaggedBaseConfig `testtag:"required"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{
+			"TestTaggedBaseConfig",
+			"TestTaggedBaseConfig.ID",
+		}, "unexpected field paths")
+	})
+
+	t.Run("Pointer cycle does not loop", func(t *testing.T) {
+		type node struct {
+			Name string `testtag:"required"`
+			Next *node
+		}
+
+		data := node{Name: "a"}
+		data.Next = &data
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{"Name"},
+			"unexpected field paths")
+	})
+
+	t.Run("Pointer cycle back to root is skipped", func(t *testing.T) {
+		type outer struct {
+			In struct {
+				X    string `testtag:"required"`
+				Back *outer
+			}
+		}
+
+		data := &outer{}
+		data.In.Back = data
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{"In.X"},
+			"unexpected field paths")
+
+		tagSet := NewTagSet(tagSettings)
+
+		setFields, err := tagSet.ParseStruct(data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, setFields, 1, "unexpected fields len")
+	})
+
+	t.Run("Pointer to first field of root is parsed", func(t *testing.T) {
+		type inner struct {
+			X string `testtag:"required"`
+		}
+		type outer struct {
+			In  inner
+			Ptr *inner
+		}
+
+		data := &outer{}
+		data.Ptr = &data.In
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{"In.X", "Ptr.X"},
+			"unexpected field paths")
+	})
+
+	t.Run("Nested error contains path", func(t *testing.T) {
+		data := struct {
+			Server struct {
+				Host string `testtag:"unknown"`
+			}
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithRecursiveParsing().
+			AddKeys(NewKey("required", true, false, nil))
+
+		_, err := tagSettings.ParseStruct(&data)
+		testza.AssertNotNil(t, err, "expected error")
+		testza.AssertContains(t, err.Error(), "Server.Host",
+			"unexpected error message")
+	})
+}
+
+type testItem struct {
+	Name string `testtag:"required"`
+}
+
+type testLimit struct {
+	Max int `testtag:"required"`
+}
+
+func Test_ParseStructContainers(t *testing.T) {
+	t.Run("Containers are not traversed by default", func(t *testing.T) {
+		data := struct {
+			Items []testItem
+		}{Items: []testItem{{Name: "a"}}}
+
+		fields, err := NewSettings("testtag").
+			AddKeys(NewKey("required", true, false, nil)).
+			ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 0, "unexpected fields len")
+	})
+
+	t.Run("Slice, array and map elements", func(t *testing.T) {
+		data := struct {
+			Items  []testItem
+			Ptrs   [2]*testItem
+			Limits map[string]testLimit
+		}{
+			Items:  []testItem{{Name: "a"}, {Name: "b"}},
+			Ptrs:   [2]*testItem{nil, {Name: "c"}},
+			Limits: map[string]testLimit{"us": {Max: 2}, "eu": {Max: 1}},
+		}
+
+		tagSettings := NewSettings("testtag").
+			WithContainerTraversal().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{
+			"Items[0].Name",
+			"Items[1].Name",
+			"Ptrs[1].Name",
+			`Limits["eu"].Max`,
+			`Limits["us"].Max`,
+		}, "unexpected field paths")
+		testza.AssertEqual(t, fields[3].Value.Int(), int64(1),
+			"unexpected value")
+	})
+
+	t.Run("Slice elements can be changed", func(t *testing.T) {
+		data := struct {
+			Items []testItem
+		}{Items: []testItem{{Name: "a"}}}
+
+		tagSettings := NewSettings("testtag").
+			WithContainerTraversal().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertNoError(t, fields[0].SetValue("b"), "unexpected error")
+		testza.AssertEqual(t, data.Items[0].Name, "b", "unexpected value")
+	})
+
+	t.Run("Nested containers and integer map keys", func(t *testing.T) {
+		data := struct {
+			Grid  [][]testItem
+			ByID  map[int]*testItem
+			Names []string
+		}{
+			Grid:  [][]testItem{{{Name: "a"}}, {{Name: "b"}}},
+			ByID:  map[int]*testItem{10: {Name: "c"}, 2: {Name: "d"}},
+			Names: []string{"ignored"},
+		}
+
+		tagSettings := NewSettings("testtag").
+			WithContainerTraversal().
+			AddKeys(NewKey("required", true, false, nil))
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testPaths(fields), []string{
+			"Grid[0][0].Name",
+			"Grid[1][0].Name",
+			"ByID[2].Name",
+			"ByID[10].Name",
+		}, "unexpected field paths")
+	})
+
+	t.Run("Element error contains indexed path", func(t *testing.T) {
+		type unknownItem struct {
+			Name string `testtag:"unknown"`
+		}
+
+		data := struct {
+			Items []unknownItem
+		}{Items: []unknownItem{{}}}
+
+		tagSettings := NewSettings("testtag").
+			WithContainerTraversal().
+			AddKeys(NewKey("required", true, false, nil))
+
+		_, err := tagSettings.ParseStruct(&data)
+		testza.AssertNotNil(t, err, "expected error")
+		testza.AssertContains(t, err.Error(), "Items[0].Name",
+			"unexpected error message")
+	})
+}
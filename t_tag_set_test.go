@@ -0,0 +1,127 @@
+package gotags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type testTagSetStruct struct {
+	Name    string `validate:"required" db:"column=name" env:"NAME"`
+	Age     int    `validate:"gt:10"`
+	Country string `db:"column=country"`
+	Note    string
+}
+
+func newTestTagSet(processor Processor) *TagSet {
+	return NewTagSet(
+		NewSettings("validate").
+			WithProcessor(processor).
+			AddKeys(
+				NewKey("required", true, false, nil),
+				NewKey("gt", false, false, nil).WithType(ValueInt),
+			),
+		NewSettings("db").
+			WithCustomSeparators(",", "=").
+			AddKeys(NewKey("column", false, true, nil)),
+		NewSettings("env").
+			WithNoKeyExistValidation(),
+	)
+}
+
+func Test_TagSet(t *testing.T) {
+	t.Run("Tags are grouped by namespace", func(t *testing.T) {
+		fields, err := newTestTagSet(nil).ParseStruct(&testTagSetStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testSetPaths(fields), []string{"Name", "Age", "Country"},
+			"unexpected field paths")
+
+		name := fields[0]
+		testza.AssertEqual(t, name.TagsOf("validate"), []Tag{{Key: "required"}},
+			"unexpected validate tags")
+		testza.AssertEqual(t, name.TagsOf("db"), []Tag{{Key: "column", Value: "name"}},
+			"unexpected db tags")
+		testza.AssertEqual(t, name.TagsOf("env"), []Tag{{Key: "NAME"}},
+			"unexpected env tags")
+		testza.AssertLen(t, name.Tags, 3, "unexpected all tags len")
+
+		testza.AssertEqual(t, fields[1].TagsOf("validate")[0].Int(), 10,
+			"unexpected typed value")
+		testza.AssertNil(t, fields[1].TagsOf("db"), "expected no db tags")
+
+		_, ok := fields[2].Namespace("validate")
+		testza.AssertFalse(t, ok, "expected field not in validate namespace")
+
+		dbField, ok := fields[2].Namespace("db")
+		testza.AssertTrue(t, ok, "expected field in db namespace")
+		testza.AssertEqual(t, dbField.KeyValue("column"), "country",
+			"unexpected db column")
+	})
+
+	t.Run("Namespace processors run with own tags", func(t *testing.T) {
+		var processed []string
+
+		tagSet := newTestTagSet(func(field Field) error {
+			processed = append(processed, field.Name+":"+field.FirstTag().Key)
+			return nil
+		})
+
+		_, err := tagSet.ParseStruct(&testTagSetStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, processed, []string{"Name:required", "Age:gt"},
+			"unexpected processed fields")
+	})
+
+	t.Run("Namespace errors are reported", func(t *testing.T) {
+		data := struct {
+			Name string `validate:"required" db:"unknown=1"`
+		}{}
+
+		fields, err := newTestTagSet(nil).ParseStruct(&data)
+		testza.AssertNil(t, fields, "fields expected as nil")
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		testza.AssertEqual(t, parseErr.Tag, "db", "unexpected namespace")
+		testza.AssertEqual(t, parseErr.Field, "Name", "unexpected field")
+	})
+
+	t.Run("Member changes drop compiled plans", func(t *testing.T) {
+		tagSet := newTestTagSet(nil)
+
+		_, err := tagSet.ParseStruct(&testTagSetStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+
+		tagSet.Settings[0].RemoveKey("gt")
+
+		_, err = tagSet.ParseStruct(&testTagSetStruct{})
+		testza.AssertTrue(t, errors.Is(err, ErrUnknownKey), "expected unknown key")
+	})
+
+	t.Run("Nested structs with recursive member", func(t *testing.T) {
+		type inner struct {
+			Host string `validate:"required" db:"column=host"`
+		}
+		data := struct {
+			Server inner
+		}{}
+
+		tagSet := newTestTagSet(nil)
+		tagSet.Settings[0].WithRecursiveParsing()
+
+		fields, err := tagSet.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, testSetPaths(fields), []string{"Server.Host"},
+			"unexpected field paths")
+		testza.AssertLen(t, fields[0].TagsOf("db"), 1, "unexpected db tags")
+	})
+}
+
+func testSetPaths(fields []SetField) []string {
+	paths := make([]string, len(fields))
+	for i, field := range fields {
+		paths[i] = field.Path
+	}
+	return paths
+}
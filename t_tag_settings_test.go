@@ -1,11 +1,13 @@
 package gotags
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MarvinJWendt/testza"
 )
@@ -470,3 +472,432 @@ func Test_ParseStruct(t *testing.T) {
 		testza.AssertNil(t, fields, "fields expected as nil")
 	})
 }
+
+var aliasKeys = []Key{
+	NewKey("required", true, false, nil).
+		WithAliases("mandatory").
+		WithDeprecated("req"),
+	NewKey("equals", false, false, nil).
+		WithDeprecated("eq"),
+}
+
+func Test_KeyAliases(t *testing.T) {
+	type testAliasStruct struct {
+		Name  string `validator:"required;equals:john"`
+		Alias string `validator:"mandatory;eq:jane"`
+		Old   string `validator:"req"`
+	}
+
+	t.Run("Tags hold canonical key names", func(t *testing.T) {
+		settings := NewSettings("validator").AddKeys(aliasKeys...)
+
+		fields, err := settings.ParseStruct(&testAliasStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, fields[1].Tags, []Tag{
+			{Key: "required"},
+			{Key: "equals", Value: "jane"},
+		}, "unexpected alias tags")
+		testza.AssertTrue(t, fields[2].HasKey("required"), "expected canonical key")
+	})
+
+	t.Run("Deprecated names are reported once per type", func(t *testing.T) {
+		var deprecations []string
+		settings := NewSettings("validator").
+			WithDeprecationHandler(func(deprecation Deprecation) {
+				deprecations = append(deprecations, deprecation.String())
+			}).
+			AddKeys(aliasKeys...)
+
+		for i := 0; i < 2; i++ {
+			_, err := settings.ParseStruct(&testAliasStruct{})
+			testza.AssertNoError(t, err, "unexpected error")
+		}
+
+		testza.AssertEqual(t, deprecations, []string{
+			"field 'Alias': tag key 'eq' is deprecated, use 'equals'",
+			"field 'Old': tag key 'req' is deprecated, use 'required'",
+		}, "unexpected deprecations")
+	})
+
+	t.Run("Errors keep used key name", func(t *testing.T) {
+		settings := NewSettings("validator").AddKeys(aliasKeys...)
+
+		_, err := settings.ParseStructTag(`validator:"eq"`)
+		testza.AssertErrorIs(t, err, ErrMissingArgument, "expected missing argument")
+		testza.AssertContains(t, err.Error(), "tag 'eq' requires argument")
+	})
+}
+
+var duplicateKeys = []Key{
+	NewKey("gt", false, false, nil),
+	NewKey("lt", false, false, nil),
+	NewKey("oneof", false, false, nil).WithDuplicates(DuplicatesAccumulate),
+	NewKey("header", false, false, nil).WithDuplicates(DuplicatesAccumulate),
+}
+
+func Test_DuplicateKeys(t *testing.T) {
+	type testDuplicateStruct struct {
+		Value  string `validator:"gt:1;oneof:a;lt:9;gt:5;oneof:b"`
+		Header string `validator:"header:X-A;header:X-B"`
+	}
+
+	t.Run("Accumulate by default", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithDuplicatePolicy(DuplicatesDefault).
+			AddKeys(duplicateKeys...)
+
+		fields, err := settings.ParseStruct(&testDuplicateStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, fields[0].KeyValues("gt"), []string{"1", "5"},
+			"unexpected values")
+		testza.AssertEqual(t, fields[0].KeyValue("gt"), "1", "unexpected first value")
+		testza.AssertEqual(t, fields[1].KeyValues("header"), []string{"X-A", "X-B"},
+			"unexpected values")
+		testza.AssertNil(t, fields[1].KeyValues("gt"), "expected no values")
+	})
+
+	t.Run("Keep last", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithDuplicatePolicy(DuplicatesKeepLast).
+			AddKeys(duplicateKeys...)
+
+		fields, err := settings.ParseStruct(&testDuplicateStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, fields[0].Tags, []Tag{
+			{Key: "oneof", Value: "a"},
+			{Key: "lt", Value: "9"},
+			{Key: "gt", Value: "5"},
+			{Key: "oneof", Value: "b"},
+		}, "unexpected tags")
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithDuplicatePolicy(DuplicatesReject).
+			AddKeys(duplicateKeys...)
+
+		_, err := settings.ParseStruct(&testDuplicateStruct{})
+		testza.AssertErrorIs(t, err, ErrDuplicateKey, "expected duplicate key error")
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		testza.AssertEqual(t, parseErr.Error(), "field 'Value': tag 'gt' is duplicated",
+			"unexpected message")
+		testza.AssertEqual(t, parseErr.Offset, 18, "unexpected offset")
+	})
+
+	t.Run("Aliases are duplicates of canonical key", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithDuplicatePolicy(DuplicatesReject).
+			AddKeys(NewKey("equals", false, false, nil).WithAliases("eq"))
+
+		_, err := settings.ParseStructTag(`validator:"eq:1;equals:2"`)
+		testza.AssertErrorIs(t, err, ErrDuplicateKey, "expected duplicate key error")
+	})
+}
+
+func Test_KeyFieldTypes(t *testing.T) {
+	settings := NewSettings("validator").
+		WithCollectAllErrors().
+		AddKeys(
+			NewKey("gt", false, false, nil).
+				WithKinds(reflect.Int, reflect.Uint, reflect.Float64),
+			NewKey("regex", false, false, nil).WithKinds(reflect.String),
+			NewKey("max", false, false, nil).
+				WithKinds(reflect.Int).
+				WithTypes(reflect.TypeOf(time.Duration(0))),
+		)
+
+	t.Run("Applicable fields", func(t *testing.T) {
+		type testStruct struct {
+			Age     int            `validator:"gt:10;max:100"`
+			Score   *float64       `validator:"gt:1"`
+			Name    string         `validator:"regex:^a"`
+			Timeout *time.Duration `validator:"max:1m"`
+		}
+
+		fields, err := settings.ParseStruct(&testStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 4, "unexpected field count")
+	})
+
+	t.Run("Not applicable fields", func(t *testing.T) {
+		type testStruct struct {
+			Enabled bool          `validator:"gt:10"`
+			Age     int           `validator:"regex:^a"`
+			Window  time.Duration `validator:"gt:1"`
+		}
+
+		_, err := settings.ParseStruct(&testStruct{})
+		testza.AssertErrorIs(t, err, ErrFieldTypeMismatch, "expected type error")
+		testza.AssertEqual(t, err.Error(), strings.Join([]string{
+			"field 'Enabled': tag 'gt' cannot be used on field of type bool",
+			"field 'Age': tag 'regex' cannot be used on field of type int",
+			"field 'Window': tag 'gt' cannot be used on field of type time.Duration",
+		}, "\n"), "unexpected errors")
+	})
+
+	t.Run("Tag parsing without field skips check", func(t *testing.T) {
+		tags, err := settings.ParseStructTag(`validator:"gt:10"`)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, tags, 1, "unexpected tag count")
+	})
+}
+
+func Test_ContextValidator(t *testing.T) {
+	fitsField := func(ctx ValidationContext) error {
+		bits := ctx.Field.Type.Bits()
+		limit := int64(1)<<(bits-1) - 1
+
+		if int64(ctx.Tag.Int()) > limit {
+			return fmt.Errorf("value %d overflows %s", ctx.Tag.Int(), ctx.Field.Type)
+		}
+
+		return nil
+	}
+
+	var contexts []ValidationContext
+	settings := NewSettings("validator").
+		WithCollectAllErrors().
+		AddKeys(
+			NewKey("min", false, false, nil).
+				WithType(ValueInt).
+				WithAliases("gte").
+				WithContextValidator(fitsField),
+			NewKey("max", false, false, nil).
+				WithContextValidator(func(ctx ValidationContext) error {
+					contexts = append(contexts, ctx)
+					return nil
+				}),
+		)
+
+	type testStruct struct {
+		Small int8  `validator:"max:1;gte:300"`
+		Large int32 `validator:"min:300"`
+	}
+
+	_, err := settings.ParseStruct(&testStruct{})
+	testza.AssertErrorIs(t, err, ErrInvalidValue, "expected invalid value")
+	testza.AssertEqual(t, err.Error(), "field 'Small': value 300 overflows int8",
+		"unexpected error")
+
+	testza.AssertLen(t, contexts, 1, "expected single context")
+	testza.AssertEqual(t, contexts[0].Field.Name, "Small", "unexpected field")
+	testza.AssertEqual(t, contexts[0].Key.Name, "max", "unexpected key")
+	testza.AssertEqual(t, contexts[0].Tag.Value, "1", "unexpected tag")
+	testza.AssertEqual(t, contexts[0].Tags[1].Key, "min", "expected canonical sibling")
+}
+
+func Test_ParseStructContext(t *testing.T) {
+	type testStruct struct {
+		Name    string `validator:"default"`
+		Country string `validator:"default"`
+		City    string `validator:"default"`
+	}
+
+	type ctxKey struct{}
+
+	t.Run("Context is passed to processor", func(t *testing.T) {
+		var processed []string
+		settings := NewSettings("validator").
+			WithNoKeyExistValidation().
+			WithProcessor(func(field Field) error {
+				processed = append(processed, "processor:"+field.Name)
+				return nil
+			}).
+			WithContextProcessor(func(ctx context.Context, field Field) error {
+				processed = append(processed, ctx.Value(ctxKey{}).(string)+":"+field.Name)
+				return nil
+			})
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "ctx")
+
+		_, err := settings.ParseStructContext(ctx, &testStruct{})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, processed, []string{
+			"processor:Name", "ctx:Name",
+			"processor:Country", "ctx:Country",
+			"processor:City", "ctx:City",
+		}, "unexpected processing order")
+	})
+
+	t.Run("Processing stops when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var processed []string
+		settings := NewSettings("validator").
+			WithNoKeyExistValidation().
+			WithCollectAllErrors().
+			WithContextProcessor(func(ctx context.Context, field Field) error {
+				processed = append(processed, field.Name)
+				cancel()
+				return nil
+			})
+
+		fields, err := settings.ParseStructContext(ctx, &testStruct{})
+		testza.AssertErrorIs(t, err, context.Canceled, "expected canceled context")
+		testza.AssertNil(t, fields, "expected no fields")
+		testza.AssertEqual(t, processed, []string{"Name"}, "expected single field")
+	})
+}
+
+// testCaseHandler trims or uppercases field value depending on tag key and
+// records calls, handler of failKey fails.
+func testCaseHandler(calls *[]string, failKey string) Handler {
+	return func(field Field, tag Tag) error {
+		*calls = append(*calls, field.Name+":"+tag.Key)
+		if tag.Key == failKey {
+			return errors.New("handler failed")
+		}
+
+		value := field.Value.String()
+		switch tag.Key {
+		case "trim":
+			value = strings.TrimSpace(value)
+		case "upper":
+			value = strings.ToUpper(value)
+		}
+
+		return field.SetValue(value)
+	}
+}
+
+func Test_KeyHandlers(t *testing.T) {
+	type testStruct struct {
+		Name  string `validator:"trim;upper;required"`
+		Email string `validator:"trim"`
+		Note  string `validator:"required"`
+	}
+
+	t.Run("Handlers run in tag order", func(t *testing.T) {
+		var calls []string
+		handler := testCaseHandler(&calls, "")
+		settings := NewSettings("validator").
+			WithCollectAllErrors().
+			WithProcessor(func(field Field) error {
+				calls = append(calls, field.Name+":processor")
+				return nil
+			}).
+			AddKeys(
+				NewKey("trim", true, false, nil).WithHandler(handler),
+				NewKey("upper", true, false, nil).WithHandler(handler),
+				NewKey("required", true, false, nil),
+			)
+
+		data := &testStruct{Name: "  john ", Email: " a@b.c "}
+
+		_, err := settings.ParseStruct(data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, data.Name, "JOHN", "unexpected name")
+		testza.AssertEqual(t, data.Email, "a@b.c", "unexpected email")
+		testza.AssertEqual(t, calls, []string{
+			"Name:trim", "Name:upper", "Name:processor",
+			"Email:trim", "Email:processor",
+			"Note:processor",
+		}, "unexpected calls")
+	})
+
+	t.Run("Handler error names key", func(t *testing.T) {
+		var calls []string
+		handler := testCaseHandler(&calls, "upper")
+		settings := NewSettings("validator").
+			WithCollectAllErrors().
+			WithProcessor(func(field Field) error {
+				calls = append(calls, field.Name+":processor")
+				return nil
+			}).
+			AddKeys(
+				NewKey("trim", true, false, nil).WithHandler(handler),
+				NewKey("upper", true, false, nil).WithHandler(handler),
+				NewKey("required", true, false, nil),
+			)
+
+		_, err := settings.ParseStruct(&testStruct{})
+		testza.AssertErrorIs(t, err, ErrProcessorFailed, "expected processor error")
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		testza.AssertEqual(t, parseErr.Field, "Name", "unexpected field")
+		testza.AssertEqual(t, parseErr.Key, "upper", "unexpected key")
+		testza.AssertEqual(t, calls, []string{
+			"Name:trim", "Name:upper",
+			"Email:trim", "Email:processor",
+			"Note:processor",
+		}, "unexpected calls")
+	})
+}
+
+var rangeKeys = []Key{
+	NewKey("limit", true, false, nil),
+	NewKey("contact", true, false, nil),
+}
+
+func Test_StructProcessor(t *testing.T) {
+	type testRange struct {
+		Min   int    `validator:"limit"`
+		Max   int    `validator:"limit"`
+		Email string `validator:"contact"`
+		Phone string `validator:"contact"`
+	}
+
+	minBelowMax := func(root reflect.Value, fields []Field) error {
+		if root.FieldByName("Min").Int() >= root.FieldByName("Max").Int() {
+			return errors.New("'Min' must be less than 'Max'")
+		}
+
+		return nil
+	}
+
+	anyContact := func(root reflect.Value, fields []Field) error {
+		for _, field := range fields {
+			if field.HasKey("contact") && !field.Value.IsZero() {
+				return nil
+			}
+		}
+
+		return errors.New("at least one contact must be set")
+	}
+
+	t.Run("Struct processor gets all fields", func(t *testing.T) {
+		var got []Field
+		settings := NewSettings("validator").
+			WithStructProcessor(func(root reflect.Value, fields []Field) error {
+				got = fields
+				return minBelowMax(root, fields)
+			}).
+			AddKeys(rangeKeys...)
+
+		fields, err := settings.ParseStruct(&testRange{Min: 1, Max: 2})
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, len(got), 4, "unexpected field count")
+		testza.AssertEqual(t, len(fields), 4, "unexpected field count")
+	})
+
+	t.Run("Struct processor error is returned", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithStructProcessor(minBelowMax).
+			AddKeys(rangeKeys...)
+
+		_, err := settings.ParseStruct(&testRange{Min: 2, Max: 1})
+		testza.AssertEqual(t, err.Error(), "'Min' must be less than 'Max'",
+			"unexpected error")
+	})
+
+	t.Run("Struct processor error is collected", func(t *testing.T) {
+		settings := NewSettings("validator").
+			WithStructProcessor(anyContact).
+			WithCollectAllErrors().
+			AddKeys(rangeKeys...)
+
+		fields, err := settings.ParseStruct(&testRange{})
+		testza.AssertErrorIs(t, err, ErrProcessorFailed, "expected processor error")
+		testza.AssertLen(t, fields, 4, "expected parsed fields")
+
+		var parseErr *ParseError
+		testza.AssertTrue(t, errors.As(err, &parseErr), "expected ParseError")
+		testza.AssertEqual(t, parseErr.Type, reflect.TypeOf(testRange{}), "unexpected type")
+		testza.AssertEqual(t, parseErr.Error(), "at least one contact must be set",
+			"unexpected message")
+	})
+}
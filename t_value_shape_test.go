@@ -0,0 +1,89 @@
+package gotags
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var shapeKeys = []Key{
+	NewKey("oneof", false, false, nil).WithStringList("|"),
+	NewKey("replace", false, false, nil).WithMap("|", "="),
+	NewKey("requiredIf", false, false, nil).WithMap(",", ":"),
+	NewKey("check", false, false, nil).WithCall(","),
+}
+
+func Test_ValueShapes(t *testing.T) {
+	t.Run("Values are split", func(t *testing.T) {
+		data := struct {
+			Color   string `testtag:"oneof:red|green\\|blue"`
+			Text    string `testtag:"replace:old\\,value=new\\|value|a\\=b=c"`
+			Admin   string `testtag:"requiredIf:Type\\:admin|user,Role:root"`
+			Country string `testtag:"check:oneof(lv,lt\\,ee)"`
+			Time    string `testtag:"check:now()"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			AddKeys(shapeKeys...)
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 5, "unexpected fields len")
+
+		testza.AssertEqual(t, fields[0].FirstTag().Values(),
+			[]string{"red", "green|blue"}, "unexpected list values")
+
+		testza.AssertEqual(t, fields[1].FirstTag().Map(), map[string]string{
+			"old\\,value": "new|value",
+			"a=b":         "c",
+		}, "unexpected map")
+
+		testza.AssertEqual(t, fields[2].FirstTag().Map(), map[string]string{
+			"Type": "admin|user",
+			"Role": "root",
+		}, "unexpected map")
+
+		name, args := fields[3].FirstTag().Call()
+		testza.AssertEqual(t, name, "oneof", "unexpected call name")
+		testza.AssertEqual(t, args, []string{"lv", "lt,ee"}, "unexpected call args")
+		testza.AssertEqual(t, fields[3].FirstTag().Values(), args,
+			"call values must be args")
+
+		name, args = fields[4].FirstTag().Call()
+		testza.AssertEqual(t, name, "now", "unexpected call name")
+		testza.AssertLen(t, args, 0, "unexpected call args")
+	})
+
+	t.Run("Not shaped values", func(t *testing.T) {
+		tag := Tag{Key: "eq", Value: "a,b"}
+
+		testza.AssertNil(t, tag.Values(), "expected no values")
+		testza.AssertNil(t, tag.Map(), "expected no map")
+
+		name, args := tag.Call()
+		testza.AssertEqual(t, name, "a,b", "unexpected call name")
+		testza.AssertNil(t, args, "expected no args")
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			AddKeys(shapeKeys...)
+
+		for _, tag := range []string{
+			"replace:a=b|c",
+			"replace:a=b|a=c",
+			"replace:=b",
+			"check:oneof",
+			"check:oneof(a",
+			"check:(a)",
+		} {
+			_, err := tagSettings.ParseStructTag(reflect.StructTag(`testtag:"` + tag + `"`))
+			testza.AssertTrue(t, errors.Is(err, ErrInvalidValue),
+				"expected invalid value error for "+tag)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package gotags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var typedKeys = []Key{
+	NewKey("gt", false, false, nil).WithType(ValueInt),
+	NewKey("ratio", false, false, nil).WithType(ValueFloat),
+	NewKey("enabled", false, false, nil).WithType(ValueBool),
+	NewKey("timeout", false, false, nil).WithType(ValueDuration),
+	NewKey("regex", false, false, nil).WithType(ValueRegexp),
+	NewKey("mode", false, false, nil).WithEnum("fast", "slow"),
+	NewKey("oneof", false, false, nil).WithStringList(","),
+	NewKey("required", true, false, nil),
+}
+
+func Test_TypedKeyValues(t *testing.T) {
+	t.Run("Values are converted", func(t *testing.T) {
+		data := struct {
+			Age     int           `testtag:"gt:10;ratio:0.5;enabled:true"`
+			Timeout time.Duration `testtag:"timeout:1m30s;required"`
+			Name    string        `testtag:"regex:^a\\;b$;mode:fast"`
+			Color   string        `testtag:"oneof:red,green\\,blue"`
+		}{}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			AddKeys(typedKeys...)
+
+		fields, err := tagSettings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertLen(t, fields, 4, "unexpected fields len")
+
+		gt, ok := fields[0].TagByKey("gt")
+		testza.AssertTrue(t, ok, "expected gt tag")
+		testza.AssertEqual(t, gt.Int(), 10, "unexpected int value")
+
+		ratio, _ := fields[0].TagByKey("ratio")
+		testza.AssertEqual(t, ratio.Float(), 0.5, "unexpected float value")
+
+		enabled, _ := fields[0].TagByKey("enabled")
+		testza.AssertTrue(t, enabled.Bool(), "unexpected bool value")
+
+		testza.AssertEqual(t, fields[1].FirstTag().Duration(), 90*time.Second,
+			"unexpected duration value")
+
+		regex := fields[2].FirstTag().Regexp()
+		testza.AssertNotNil(t, regex, "expected compiled regexp")
+		testza.AssertTrue(t, regex.MatchString("a;b"), "unexpected regexp")
+
+		testza.AssertEqual(t, fields[3].FirstTag().Strings(),
+			[]string{"red", "green,blue"}, "unexpected list value")
+	})
+
+	t.Run("Invalid values return error", func(t *testing.T) {
+		testCases := []struct {
+			Name string
+			Data any
+		}{
+			{"int", &struct {
+				Age int `testtag:"gt:ten"`
+			}{}},
+			{"float", &struct {
+				Ratio float64 `testtag:"ratio:half"`
+			}{}},
+			{"bool", &struct {
+				On bool `testtag:"enabled:maybe"`
+			}{}},
+			{"duration", &struct {
+				Timeout time.Duration `testtag:"timeout:soon"`
+			}{}},
+			{"regexp", &struct {
+				Name string `testtag:"regex:(a"`
+			}{}},
+			{"enum", &struct {
+				Name string `testtag:"mode:medium"`
+			}{}},
+		}
+
+		tagSettings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			AddKeys(typedKeys...)
+
+		for _, testCase := range testCases {
+			fields, err := tagSettings.ParseStruct(testCase.Data)
+			testza.AssertNotNil(t, err, "expected error for "+testCase.Name)
+			testza.AssertNil(t, fields, "fields expected as nil")
+		}
+	})
+
+	t.Run("Untyped tags convert on the fly", func(t *testing.T) {
+		tag, err := NewTagFromString("gt:0x10", ":")
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, tag.Int(), 16, "unexpected int value")
+		testza.AssertNil(t, tag.Strings(), "expected no list value")
+	})
+
+	t.Run("String keys keep plain tags", func(t *testing.T) {
+		data := struct {
+			Name string `testtag:"eq:value"`
+		}{}
+
+		fields, err := NewSettings("testtag").
+			AddKeys(NewKey("eq", false, false, nil)).
+			ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+		testza.AssertEqual(t, fields[0].FirstTag(), Tag{Key: "eq", Value: "value"},
+			"unexpected tag")
+	})
+	t.Run("Typed tags are comparable", func(t *testing.T) {
+		data := struct {
+			Color string `testtag:"oneof:red,green"`
+		}{}
+
+		settings := NewSettings("testtag").
+			WithEscapeCharacter('\\').
+			AddKeys(typedKeys...)
+
+		first, err := settings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+
+		second, err := settings.ParseStruct(&data)
+		testza.AssertNoError(t, err, "unexpected error")
+
+		testza.AssertTrue(t, first[0].FirstTag() == second[0].FirstTag(),
+			"expected equal tags")
+		testza.AssertFalse(t, first[0].FirstTag() == Tag{Key: "oneof", Value: "red"},
+			"expected different tags")
+		testza.AssertEqual(t, first[0].FirstTag().Strings(), []string{"red", "green"},
+			"unexpected list value")
+	})
+}
@@ -1,13 +1,29 @@
 package gotags
 
 import (
-	"errors"
 	"fmt"
+	"reflect"
 )
 
 type Tag struct {
 	Key   string
 	Value string
+	typed *typedValue // Value converted to Key.Type, see Tag.Int() and others.
+}
+
+// typedValue holds converted tag value. It is kept behind pointer, so Tag
+// stays comparable whatever the converted value is.
+type typedValue struct {
+	value any
+}
+
+// converted returns converted tag value, nil if value is not typed.
+func (tag Tag) converted() any {
+	if tag.typed == nil {
+		return nil
+	}
+
+	return tag.typed.value
 }
 
 func NewTagFromString(tagStr, equals string) (Tag, error) {
@@ -33,7 +49,7 @@ func newTagFromString(tagStr, equals string, escapeCharacter byte) (Tag, error)
 	}
 
 	if key == "" && tagStr == "" {
-		return Tag{}, errors.New("no keys defined")
+		return Tag{}, ErrEmptyTag
 	}
 
 	tag := Tag{
@@ -48,19 +64,57 @@ func newTagFromString(tagStr, equals string, escapeCharacter byte) (Tag, error)
 	return tag, nil
 }
 
-func (tag *Tag) validate(key *Key) error {
+func (tag *Tag) validate(key *Key, escapeCharacter byte) error {
 	if key.IsBool && tag.Value != "" {
-		return fmt.Errorf("tag '%s' does not take any arguments", tag.Key)
+		return newParseError(ErrUnexpectedArgument, tag.Key,
+			fmt.Errorf("tag '%s' does not take any arguments", tag.Key))
 	}
 	if !key.IsBool && tag.Value == "" {
-		return fmt.Errorf("tag '%s' requires argument", tag.Key)
+		return newParseError(ErrMissingArgument, tag.Key,
+			fmt.Errorf("tag '%s' requires argument", tag.Key))
+	}
+
+	if !key.IsBool {
+		typed, err := key.convertValue(tag.Value, escapeCharacter)
+		if err != nil {
+			return newParseError(ErrInvalidValue, tag.Key, err)
+		}
+
+		if typed != nil {
+			tag.typed = &typedValue{value: typed}
+		}
 	}
 
 	if key.Validator == nil {
 		return nil
 	}
 
-	return key.Validator(tag.Value)
+	err := key.Validator(tag.Value)
+	if err != nil {
+		return newParseError(ErrInvalidValue, tag.Key, err)
+	}
+
+	return nil
+}
+
+// validateContext runs context validator of key, tags are all tags of
+// structField.
+func (tag *Tag) validateContext(
+	key *Key,
+	structField reflect.StructField,
+	tags []Tag,
+) error {
+	err := key.ContextValidator(ValidationContext{
+		Tag:   *tag,
+		Key:   *key,
+		Field: structField,
+		Tags:  tags,
+	})
+	if err != nil {
+		return newParseError(ErrInvalidValue, tag.Key, err)
+	}
+
+	return nil
 }
 
 // StringFormatted formats key and value in provided format.
@@ -0,0 +1,514 @@
+package gotags
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// TagSet combines several TagSettings (tag namespaces) and parses all of them
+// in a single struct walk, like `validate:"..." db:"..." env:"..."`.
+// Each namespace keys are validated by its own TagSettings and each
+// namespace processor runs for fields tagged with that namespace.
+type TagSet struct {
+	Settings []*TagSettings
+
+	mu    sync.Mutex
+	plans *tagSetPlans
+}
+
+// SetField is a field parsed by TagSet.
+// Field.Tags holds tags of all namespaces in TagSet order, use TagsOf or
+// Namespace to get tags of single namespace.
+type SetField struct {
+	Field
+	plan *setFieldPlan
+}
+
+// tagSetPlans holds compiled plans and member plan caches they were compiled
+// with, plans get dropped when any member settings change.
+type tagSetPlans struct {
+	members []*planCache
+	plans   sync.Map // reflect.Type => *setStructPlan
+}
+
+type setStructPlan struct {
+	fields []setFieldPlan
+	hooks  structHooks
+}
+
+type setFieldPlan struct {
+	index    []int
+	name     string
+	kind     reflect.Kind
+	names    []string       // Namespace names, same order as TagSet.Settings.
+	settings []*TagSettings // Namespace settings plan was compiled with.
+	tags     [][]Tag        // Tags per namespace.
+	include  []bool         // Field is included per namespace.
+	allTags  []Tag          // Tags of all namespaces.
+	errs     []*ParseError
+	any      bool // Field is included by at least one namespace.
+	descend  bool
+	hook     bool // Field type implements TagFieldProcessor.
+}
+
+// NewTagSet creates TagSet of passed settings.
+// Struct is walked into nested structs or containers if any of settings
+// enables it, errors are collected if any of settings collects them.
+func NewTagSet(settings ...*TagSettings) *TagSet {
+	return &TagSet{
+		Settings: settings,
+	}
+}
+
+// TagsOf returns tags of namespace name.
+func (field SetField) TagsOf(name string) []Tag {
+	for idx, v := range field.plan.names {
+		if v == name {
+			return slices.Clone(field.plan.tags[idx])
+		}
+	}
+
+	return nil
+}
+
+// Namespace returns field with tags of namespace name only.
+// Returns ok(false) if field is not included by that namespace.
+func (field SetField) Namespace(name string) (Field, bool) {
+	for idx, v := range field.plan.names {
+		if v != name || !field.plan.include[idx] {
+			continue
+		}
+
+		return field.namespaceField(idx), true
+	}
+
+	return Field{}, false
+}
+
+// namespaceField returns field with tags of namespace at idx only.
+func (field SetField) namespaceField(idx int) Field {
+	namespaceField := field.Field
+	namespaceField.Tags = slices.Clone(field.plan.tags[idx])
+	namespaceField.settings = field.plan.settings[idx]
+	return namespaceField
+}
+
+// ParseStruct parses passed struct once for every namespace and triggers
+// validators and processors of every namespace.
+func (ts *TagSet) ParseStruct(data any) ([]SetField, error) {
+	return ts.ParseStructContext(context.Background(), data)
+}
+
+// ParseStructContext works like ParseStruct, ctx is passed to context
+// processors. Processing stops between fields as soon as ctx is done.
+func (ts *TagSet) ParseStructContext(ctx context.Context, data any) ([]SetField, error) {
+	if len(ts.Settings) == 0 {
+		return nil, errors.New("tag set has no settings")
+	}
+
+	first := ts.Settings[0]
+	valueOf := reflect.ValueOf(data)
+
+	err := first.mustValidPtr(valueOf)
+	if err != nil {
+		return nil, err
+	}
+
+	structure, err := first.unpackPtr(valueOf)
+	if err != nil {
+		return nil, err
+	}
+
+	if structure.Kind() != reflect.Struct {
+		return nil, errors.New("passed value must be pointer of struct")
+	}
+
+	state := newParseState(ctx, structure)
+	plans := ts.currentPlans()
+
+	fields, err := ts.appendStructFields(
+		make([]SetField, 0, structure.NumField()),
+		structure,
+		"",
+		state,
+		plans,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.runProcessors(fields, state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.runHooks(fields, state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.runStructProcessors(structure, fields, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.errs) > 0 {
+		return fields, errors.Join(state.errs...)
+	}
+
+	return fields, nil
+}
+
+func (ts *TagSet) appendStructFields(
+	fields []SetField,
+	valueOf reflect.Value,
+	prefix string,
+	state *parseState,
+	plans *tagSetPlans,
+) ([]SetField, error) {
+	plan := ts.structPlan(plans, valueOf.Type())
+	start := len(fields)
+
+	if plan.hooks.before {
+		err := callBeforeHook(valueOf, prefix, ts.collectErrors())
+		if err != nil {
+			err = ts.fail(state, err)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for idx := range plan.fields {
+		fieldPlan := &plan.fields[idx]
+
+		fieldValue, ok := fieldByIndex(valueOf, fieldPlan.index)
+		if !ok {
+			continue
+		}
+
+		path := prefix + fieldPlan.name
+
+		for _, parseErr := range fieldPlan.errs {
+			err := ts.fail(state, parseErr.withField(path))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if fieldPlan.any {
+			fields = append(fields, SetField{
+				Field: Field{
+					Value:      fieldValue,
+					Name:       fieldPlan.name,
+					Path:       path,
+					Kind:       fieldPlan.kind,
+					Tags:       slices.Clone(fieldPlan.allTags),
+					parent:     valueOf,
+					parentPath: prefix,
+				},
+				plan: fieldPlan,
+			})
+
+			if fieldPlan.hook {
+				state.fieldHooks = append(state.fieldHooks, len(fields)-1)
+			}
+		}
+
+		if !fieldPlan.descend {
+			continue
+		}
+
+		err := walkChildValue(fieldValue, path, state,
+			func(valueOf reflect.Value, prefix string) error {
+				var err error
+				fields, err = ts.appendStructFields(fields, valueOf, prefix, state, plans)
+				return err
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if plan.hooks.after {
+		state.hooks = append(state.hooks, pendingHook{
+			value:  valueOf,
+			prefix: prefix,
+			start:  start,
+			end:    len(fields),
+		})
+	}
+
+	return fields, nil
+}
+
+// runProcessors runs processor of every namespace, namespace by namespace.
+func (ts *TagSet) runProcessors(fields []SetField, state *parseState) error {
+	for idx, tg := range ts.Settings {
+		if tg.Processor == nil && tg.ContextProcessor == nil && !tg.hasHandlers() {
+			continue
+		}
+
+		for _, field := range fields {
+			if !field.plan.include[idx] {
+				continue
+			}
+
+			err := state.ctx.Err()
+			if err != nil {
+				return err
+			}
+
+			namespaceField := field.namespaceField(idx)
+
+			key, err := tg.processField(state.ctx, namespaceField)
+			if err == nil {
+				continue
+			}
+
+			if ts.collectErrors() {
+				err = newFieldError(ErrProcessorFailed, namespaceField, err).withKey(key)
+			}
+
+			err = ts.fail(state, err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// runHooks runs ProcessTagField of fields, then AfterTagParse of parsed
+// structs. Hooks run once, not per namespace, and get tags of all
+// namespaces.
+func (ts *TagSet) runHooks(fields []SetField, state *parseState) error {
+	for _, idx := range state.fieldHooks {
+		err := state.ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = callFieldHook(fields[idx].Field)
+		if err == nil {
+			continue
+		}
+
+		if ts.collectErrors() {
+			err = newFieldError(ErrProcessorFailed, fields[idx].Field, err)
+		}
+
+		err = ts.fail(state, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pending := range state.hooks {
+		err := state.ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		structFields := make([]Field, 0, pending.end-pending.start)
+		for _, field := range fields[pending.start:pending.end] {
+			structFields = append(structFields, field.Field)
+		}
+
+		err = callAfterHook(pending, structFields, ts.collectErrors())
+		if err == nil {
+			continue
+		}
+
+		err = ts.fail(state, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runStructProcessors runs struct processor of every namespace with fields
+// of that namespace.
+func (ts *TagSet) runStructProcessors(
+	root reflect.Value,
+	fields []SetField,
+	state *parseState,
+) error {
+	for idx, tg := range ts.Settings {
+		if tg.StructProcessor == nil {
+			continue
+		}
+
+		namespaceFields := make([]Field, 0, len(fields))
+		for _, field := range fields {
+			if field.plan.include[idx] {
+				namespaceFields = append(namespaceFields, field.namespaceField(idx))
+			}
+		}
+
+		err := state.ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = tg.StructProcessor(root, namespaceFields)
+		if err == nil {
+			continue
+		}
+
+		if ts.collectErrors() {
+			err = newStructError(ErrProcessorFailed, root.Type(), err)
+		}
+
+		err = ts.fail(state, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ts *TagSet) fail(state *parseState, err error) error {
+	if !ts.collectErrors() {
+		return err
+	}
+
+	state.errs = append(state.errs, err)
+	return nil
+}
+
+func (ts *TagSet) collectErrors() bool {
+	for _, tg := range ts.Settings {
+		if tg.collectErrors {
+			return true
+		}
+	}
+
+	return false
+}
+
+// currentPlans returns plans compiled with current member settings.
+func (ts *TagSet) currentPlans() *tagSetPlans {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.plans != nil && ts.plans.isCurrent(ts.Settings) {
+		return ts.plans
+	}
+
+	ts.plans = &tagSetPlans{
+		members: make([]*planCache, len(ts.Settings)),
+	}
+	for idx, tg := range ts.Settings {
+		ts.plans.members[idx] = tg.plans
+	}
+
+	return ts.plans
+}
+
+func (plans *tagSetPlans) isCurrent(settings []*TagSettings) bool {
+	if len(plans.members) != len(settings) {
+		return false
+	}
+
+	for idx, tg := range settings {
+		if tg.plans == nil || plans.members[idx] != tg.plans {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ts *TagSet) structPlan(plans *tagSetPlans, typeOf reflect.Type) *setStructPlan {
+	if plan, ok := plans.plans.Load(typeOf); ok {
+		return plan.(*setStructPlan)
+	}
+
+	plan, _ := plans.plans.LoadOrStore(typeOf, ts.compileStructPlan(typeOf))
+	return plan.(*setStructPlan)
+}
+
+func (ts *TagSet) compileStructPlan(typeOf reflect.Type) *setStructPlan {
+	recursive := false
+	for _, tg := range ts.Settings {
+		recursive = recursive || tg.recursive
+	}
+
+	names := make([]string, len(ts.Settings))
+	for idx, tg := range ts.Settings {
+		names[idx] = tg.Name
+	}
+
+	settings := append([]*TagSettings(nil), ts.Settings...)
+
+	infos := collectStructFields(typeOf, recursive, ts.isTagged)
+	plan := &setStructPlan{
+		fields: make([]setFieldPlan, len(infos)),
+		hooks:  typeHooks(typeOf),
+	}
+
+	for idx, info := range infos {
+		plan.fields[idx] = ts.compileFieldPlan(typeOf, info, names, settings)
+	}
+
+	return plan
+}
+
+func (ts *TagSet) compileFieldPlan(
+	typeOf reflect.Type,
+	info structFieldInfo,
+	names []string,
+	settings []*TagSettings,
+) setFieldPlan {
+	structField := info.field
+
+	fieldPlan := setFieldPlan{
+		index:    info.index,
+		name:     structField.Name,
+		kind:     structField.Type.Kind(),
+		names:    names,
+		settings: settings,
+		tags:     make([][]Tag, len(ts.Settings)),
+		include:  make([]bool, len(ts.Settings)),
+		hook:     implements(structField.Type, tagFieldProcessorType),
+	}
+
+	for idx, tg := range ts.Settings {
+		tags, errs := tg.compileTags(typeOf, structField)
+		include := len(errs) == 0 && (len(tags) > 0 || tg.IncludeNotTagged)
+
+		if include {
+			errs = tg.checkKeyRules(typeOf, structField.Tag.Get(tg.Name), tags)
+			include = len(errs) == 0
+		}
+
+		fieldPlan.tags[idx] = tags
+		fieldPlan.include[idx] = include
+		fieldPlan.errs = append(fieldPlan.errs, errs...)
+		fieldPlan.allTags = append(fieldPlan.allTags, tags...)
+		fieldPlan.any = fieldPlan.any || include
+		fieldPlan.descend = fieldPlan.descend || tg.isDescendable(structField.Type)
+	}
+
+	return fieldPlan
+}
+
+// isTagged reports whether structField is tagged with any namespace.
+func (ts *TagSet) isTagged(structField reflect.StructField) bool {
+	for _, tg := range ts.Settings {
+		if tg.isTagged(structField) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,16 +1,57 @@
 package gotags
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 )
 
+// parseState holds data of a single ParseStruct call.
+type parseState struct {
+	ctx     context.Context         // Context processors get, see ParseStructContext.
+	root    visitedPointer          // Pointer to the root struct.
+	visited map[visitedPointer]bool // Pointers being parsed, guards against cycles.
+	errs    []error                 // Collected errors, see WithCollectAllErrors.
+
+	hooks      []pendingHook // AfterTagParse calls, nested structs first.
+	fieldHooks []int         // Indexes of fields implementing TagFieldProcessor.
+}
+
+// visitedPointer is pointer being parsed. Struct and its first field share
+// address, so pointer type is part of it.
+type visitedPointer struct {
+	address uintptr
+	typeOf  reflect.Type
+}
+
+// newParseState creates state of a single ParseStruct call of root struct.
+// Root pointer is marked as being parsed, so cycles back to it are skipped.
+func newParseState(ctx context.Context, root reflect.Value) *parseState {
+	state := &parseState{ctx: ctx}
+
+	if root.CanAddr() {
+		pointer := root.Addr()
+		state.root = visitedPointer{address: pointer.Pointer(), typeOf: pointer.Type()}
+	}
+
+	return state
+}
+
 // Processor can be used to do some custom stuff for each field (if defined)
 // and gets triggered after key validation (if passed).
 type Processor func(field Field) error
 
+// ContextProcessor is Processor which gets context passed to
+// ParseStructContext, so it can be cancelled and read request-scoped values.
+type ContextProcessor func(ctx context.Context, field Field) error
+
+// StructProcessor gets called once after all fields are parsed and
+// processed, with the parsed struct and all its fields. Useful for rules
+// spanning several fields.
+type StructProcessor func(root reflect.Value, fields []Field) error
+
 // TagSettings holds data about tag.
 type TagSettings struct {
 	Name                 string
@@ -19,9 +60,19 @@ type TagSettings struct {
 	Keys                 []Key
 	Processor                 // Optional
 	IncludeNotTagged     bool // Include not tagged fields
+	ContextProcessor          // Optional, runs after Processor.
+	StructProcessor           // Optional, runs after all field processors.
 	disableKeyValidation bool // Disable key/value support, default false.
+	recursive            bool // Descend into nested and embedded structs.
+	traverseContainers   bool // Descend into slice, array and map elements.
+	collectErrors        bool // Collect all errors instead of failing on first.
 	escapeCharacter      byte
+	quoteCharacter       byte
 	keysRequired         []string
+	deprecationHandler   DeprecationHandler
+	constraints          []Constraint
+	duplicates           DuplicatePolicy
+	plans                *planCache // Compiled struct plans, nil disables caching.
 }
 
 func NewSettings(name string) *TagSettings {
@@ -29,6 +80,7 @@ func NewSettings(name string) *TagSettings {
 		Name:      name,
 		Separator: defaultSeparator,
 		Equals:    defaultEquals,
+		plans:     newPlanCache(),
 	}
 }
 
@@ -38,6 +90,7 @@ func NewSettings(name string) *TagSettings {
 // This can be useful if tag input is dynamic and not predefined.
 func (tg *TagSettings) WithNoKeyExistValidation() *TagSettings {
 	tg.disableKeyValidation = true
+	tg.resetPlans()
 	return tg
 }
 
@@ -49,6 +102,7 @@ func (tg *TagSettings) WithCustomSeparators(separator, equals string) *TagSettin
 	tg.Separator = separator
 	tg.Equals = equals
 	tg.disableKeyValidation = false
+	tg.resetPlans()
 	return tg
 }
 
@@ -61,10 +115,37 @@ func (tg *TagSettings) WithProcessor(processor Processor) *TagSettings {
 	return tg
 }
 
+// WithContextProcessor adds field processor which gets context passed to
+// ParseStructContext (context.Background() for ParseStruct).
+// It gets called after Processor, if both are defined.
+func (tg *TagSettings) WithContextProcessor(processor ContextProcessor) *TagSettings {
+	tg.ContextProcessor = processor
+	return tg
+}
+
+// WithStructProcessor adds processor which gets called once per
+// ParseStruct, after all fields are parsed and processed. It gets the parsed
+// struct (not pointer) and all parsed fields, its errors are reported like
+// field processor errors.
+func (tg *TagSettings) WithStructProcessor(processor StructProcessor) *TagSettings {
+	tg.StructProcessor = processor
+	return tg
+}
+
 // WithEscapeCharacter enables optional escape-aware parsing.
 // By default escape parsing is disabled.
 func (tg *TagSettings) WithEscapeCharacter(escapeCharacter byte) *TagSettings {
 	tg.escapeCharacter = escapeCharacter
+	tg.resetPlans()
+	return tg
+}
+
+// WithQuoteCharacter enables quoted values, like `regex:'^foo;bar$'`.
+// Separators, equals and escape characters inside quoted value are literal,
+// quote character itself is written doubled. By default quoting is disabled.
+func (tg *TagSettings) WithQuoteCharacter(quoteCharacter byte) *TagSettings {
+	tg.quoteCharacter = quoteCharacter
+	tg.resetPlans()
 	return tg
 }
 
@@ -72,6 +153,60 @@ func (tg *TagSettings) WithEscapeCharacter(escapeCharacter byte) *TagSettings {
 // not tagged struct fields.
 func (tg *TagSettings) IncludeUntaggedFields() *TagSettings {
 	tg.IncludeNotTagged = true
+	tg.resetPlans()
+	return tg
+}
+
+// WithRecursiveParsing tells TagSettings to descend into struct fields of
+// struct type, pointers to structs and anonymous embedded structs.
+// Embedded structs which are not tagged with TagSettings name get their fields
+// promoted the same way encoding/json does it, other nested fields are
+// returned with their full dotted path, like "Server.TLS.CertFile".
+// Nil pointers are skipped.
+func (tg *TagSettings) WithRecursiveParsing() *TagSettings {
+	tg.recursive = true
+	tg.resetPlans()
+	return tg
+}
+
+// WithContainerTraversal tells TagSettings to walk into every element of
+// slice, array and map fields holding structs (or pointers to structs) and
+// parse element fields with the same TagSettings.
+// Element field paths include index or map key, like "Items[3].Name" or
+// `Limits["eu"].Max`. Map elements are not addressable, so fields parsed
+// from map values (not pointers) cannot be changed with Field.SetValue.
+func (tg *TagSettings) WithContainerTraversal() *TagSettings {
+	tg.traverseContainers = true
+	tg.resetPlans()
+	return tg
+}
+
+// WithCollectAllErrors tells ParseStruct to parse every field, validate
+// every tag and run every processor before returning. All failures are
+// returned together (errors.Join) as *ParseError values naming the field,
+// fields which parsed cleanly are returned alongside the error.
+// By default ParseStruct fails on the first problem.
+func (tg *TagSettings) WithCollectAllErrors() *TagSettings {
+	tg.collectErrors = true
+	return tg
+}
+
+// WithDeprecationHandler sets handler which gets called for every use of
+// deprecated key name (see Key.WithDeprecated). Struct types are compiled
+// once, so handler gets called once per struct type field, not on every
+// ParseStruct call.
+func (tg *TagSettings) WithDeprecationHandler(handler DeprecationHandler) *TagSettings {
+	tg.deprecationHandler = handler
+	tg.resetPlans()
+	return tg
+}
+
+// WithDuplicatePolicy sets how keys repeated in a single tag are handled,
+// like `gt:1;gt:5`. Keys can override it with Key.WithDuplicates.
+// By default every repeated tag is kept (DuplicatesAccumulate).
+func (tg *TagSettings) WithDuplicatePolicy(policy DuplicatePolicy) *TagSettings {
+	tg.duplicates = policy
+	tg.resetPlans()
 	return tg
 }
 
@@ -92,6 +227,7 @@ func (tg *TagSettings) AddKey(key Key) *TagSettings {
 	if key.IsRequired {
 		tg.keysRequired = append(tg.keysRequired, key.Name)
 	}
+	tg.resetPlans()
 	return tg
 }
 
@@ -104,11 +240,37 @@ func (tg *TagSettings) RemoveKey(name string) {
 
 		tg.Keys = append(tg.Keys[:idx], tg.Keys[idx+1:]...)
 	}
+
+	tg.resetPlans()
+}
+
+// ParseStructTag parses and validates content of tag with TagSettings name
+// without any struct value, for example, tags read from source code.
+// Returns nil tags if tag is not present. Every problem is returned as
+// *ParseError (joined with errors.Join), offsets point into tag content.
+func (tg *TagSettings) ParseStructTag(tag reflect.StructTag) ([]Tag, error) {
+	tags, errs := tg.compileTags(nil, reflect.StructField{Tag: tag})
+	if len(errs) == 0 && (len(tags) > 0 || tg.IncludeNotTagged) {
+		errs = tg.checkKeyRules(nil, tag.Get(tg.Name), tags)
+	}
+
+	if len(errs) == 0 {
+		return tags, nil
+	}
+
+	return nil, joinParseErrors(errs)
 }
 
 // ParseStruct parses passed struct and triggers validators if defined
 // and field processors if defined.
 func (tg *TagSettings) ParseStruct(data any) ([]Field, error) {
+	return tg.ParseStructContext(context.Background(), data)
+}
+
+// ParseStructContext works like ParseStruct, ctx is passed to
+// ContextProcessor. Processing stops between fields as soon as ctx is done,
+// ctx.Err() is returned then.
+func (tg *TagSettings) ParseStructContext(ctx context.Context, data any) ([]Field, error) {
 	valueOf := reflect.ValueOf(data)
 
 	err := tg.mustValidPtr(valueOf)
@@ -121,26 +283,101 @@ func (tg *TagSettings) ParseStruct(data any) ([]Field, error) {
 		return nil, err
 	}
 
-	fields, err := tg.unpackStruct(structure)
+	state := newParseState(ctx, structure)
+
+	fields, err := tg.unpackStruct(structure, state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tg.runProcessor(fields, state)
 	if err != nil {
 		return nil, err
 	}
 
-	err = tg.runProcessor(fields)
+	err = tg.runHooks(fields, state)
 	if err != nil {
 		return nil, err
 	}
 
+	err = tg.runStructProcessor(structure, fields, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.errs) > 0 {
+		return fields, errors.Join(state.errs...)
+	}
+
 	return fields, nil
 }
 
-func (tg *TagSettings) runProcessor(fields []Field) error {
-	if tg.Processor == nil {
+func (tg *TagSettings) runProcessor(fields []Field, state *parseState) error {
+	if tg.Processor == nil && tg.ContextProcessor == nil && !tg.hasHandlers() {
 		return nil
 	}
 
 	for _, field := range fields {
-		err := tg.Processor(field)
+		err := state.ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		key, err := tg.processField(state.ctx, field)
+		if err == nil {
+			continue
+		}
+
+		if tg.collectErrors {
+			err = newFieldError(ErrProcessorFailed, field, err).withKey(key)
+		}
+
+		err = tg.fail(state, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runHooks runs ProcessTagField of fields, then AfterTagParse of parsed
+// structs.
+func (tg *TagSettings) runHooks(fields []Field, state *parseState) error {
+	for _, idx := range state.fieldHooks {
+		err := state.ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = callFieldHook(fields[idx])
+		if err == nil {
+			continue
+		}
+
+		if tg.collectErrors {
+			err = newFieldError(ErrProcessorFailed, fields[idx], err)
+		}
+
+		err = tg.fail(state, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pending := range state.hooks {
+		err := state.ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = callAfterHook(pending, fields[pending.start:pending.end:pending.end],
+			tg.collectErrors)
+		if err == nil {
+			continue
+		}
+
+		err = tg.fail(state, err)
 		if err != nil {
 			return err
 		}
@@ -149,6 +386,84 @@ func (tg *TagSettings) runProcessor(fields []Field) error {
 	return nil
 }
 
+// runStructProcessor runs StructProcessor with all parsed fields of root.
+func (tg *TagSettings) runStructProcessor(
+	root reflect.Value,
+	fields []Field,
+	state *parseState,
+) error {
+	if tg.StructProcessor == nil {
+		return nil
+	}
+
+	err := state.ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	err = tg.StructProcessor(root, fields)
+	if err == nil {
+		return nil
+	}
+
+	if tg.collectErrors {
+		err = newStructError(ErrProcessorFailed, root.Type(), err)
+	}
+
+	return tg.fail(state, err)
+}
+
+// processField runs key handlers of field tags in tag order, then Processor
+// and ContextProcessor. Returns key of failed handler, empty if processor
+// failed.
+func (tg *TagSettings) processField(ctx context.Context, field Field) (string, error) {
+	for _, tag := range field.Tags {
+		key, _ := tg.findMatchingKey(tag.Key)
+		if key == nil || key.Handler == nil {
+			continue
+		}
+
+		err := key.Handler(field, tag)
+		if err != nil {
+			return tag.Key, err
+		}
+	}
+
+	if tg.Processor != nil {
+		err := tg.Processor(field)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if tg.ContextProcessor != nil {
+		return "", tg.ContextProcessor(ctx, field)
+	}
+
+	return "", nil
+}
+
+// hasHandlers reports whether any key has handler.
+func (tg *TagSettings) hasHandlers() bool {
+	for idx := range tg.Keys {
+		if tg.Keys[idx].Handler != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fail returns err, or collects it and returns nil in collect-all mode.
+func (tg *TagSettings) fail(state *parseState, err error) error {
+	if !tg.collectErrors {
+		return err
+	}
+
+	state.errs = append(state.errs, err)
+	return nil
+}
+
 func (tg *TagSettings) unpackPtr(valueOf reflect.Value) (reflect.Value, error) {
 	if valueOf.Kind() != reflect.Ptr {
 		return valueOf, nil
@@ -169,7 +484,10 @@ func (tg *TagSettings) mustValidPtr(valueOfPtr reflect.Value) error {
 	return nil
 }
 
-func (tg *TagSettings) unpackStruct(valueOf reflect.Value) ([]Field, error) {
+func (tg *TagSettings) unpackStruct(
+	valueOf reflect.Value,
+	state *parseState,
+) ([]Field, error) {
 	if valueOf.Kind() != reflect.Struct {
 		return nil, errors.New("passed value must be pointer of struct")
 	}
@@ -178,60 +496,103 @@ func (tg *TagSettings) unpackStruct(valueOf reflect.Value) ([]Field, error) {
 		return nil, nil
 	}
 
-	return tg.parseFields(valueOf)
+	return tg.parseFields(valueOf, state)
 }
 
-func (tg *TagSettings) parseFields(valueOf reflect.Value) ([]Field, error) {
+func (tg *TagSettings) parseFields(
+	valueOf reflect.Value,
+	state *parseState,
+) ([]Field, error) {
 	valueOf, err := tg.tryUnpackInterface(valueOf)
 	if err != nil {
 		return nil, err
 	}
 
-	typeOf := valueOf.Type()
-
-	fields := make([]Field, typeOf.NumField())
-	addedFields := 0
-
-	for i := 0; i < typeOf.NumField(); i++ {
-		structField := typeOf.Field(i)
-		if !structField.IsExported() ||
-			(structField.Tag == "" && !tg.IncludeNotTagged) {
-			continue
-		}
+	fields := make([]Field, 0, valueOf.NumField())
+	return tg.appendStructFields(fields, valueOf, "", state)
+}
 
-		tags, err := tg.readTagContent(structField.Tag)
+// appendStructFields binds compiled plan of valueOf type and appends parsed
+// fields. prefix is the dotted path of valueOf from the root struct.
+func (tg *TagSettings) appendStructFields(
+	fields []Field,
+	valueOf reflect.Value,
+	prefix string,
+	state *parseState,
+) ([]Field, error) {
+	plan := tg.structPlan(valueOf.Type())
+	start := len(fields)
+
+	// Returned tags are copies, all fields share single allocation.
+	tags := make([]Tag, 0, plan.tagCount)
+
+	if plan.hooks.before {
+		err := callBeforeHook(valueOf, prefix, tg.collectErrors)
 		if err != nil {
-			return nil, fmt.Errorf("field '%s': %w", structField.Name, err)
+			err = tg.fail(state, err)
+			if err != nil {
+				return nil, err
+			}
 		}
+	}
+
+	for idx := range plan.fields {
+		fieldPlan := &plan.fields[idx]
 
-		if len(tags) == 0 && !tg.IncludeNotTagged {
+		fieldValue, ok := fieldByIndex(valueOf, fieldPlan.index)
+		if !ok {
 			continue
 		}
 
-		if len(tags) > 0 {
-			err = tg.validateTags(tags)
+		path := prefix + fieldPlan.name
+
+		for _, parseErr := range fieldPlan.errs {
+			err := tg.fail(state, parseErr.withField(path))
 			if err != nil {
-				return nil, fmt.Errorf("field '%s': %w", structField.Name, err)
+				return nil, err
+			}
+		}
+
+		if fieldPlan.include {
+			fields = append(fields, Field{
+				Value:      fieldValue,
+				Name:       fieldPlan.name,
+				Path:       path,
+				Kind:       fieldPlan.kind,
+				Tags:       fieldPlan.bindTags(&tags),
+				parent:     valueOf,
+				parentPath: prefix,
+				settings:   tg,
+			})
+
+			if fieldPlan.hook {
+				state.fieldHooks = append(state.fieldHooks, len(fields)-1)
 			}
 		}
 
-		fields[addedFields] = Field{
-			Value: valueOf.Field(i),
-			Name:  structField.Name,
-			Kind:  structField.Type.Kind(),
-			Tags:  tags,
+		if !fieldPlan.descend {
+			continue
 		}
 
-		err = tg.hasRequiredKeys(fields[addedFields])
+		err := walkChildValue(fieldValue, path, state,
+			func(valueOf reflect.Value, prefix string) error {
+				var err error
+				fields, err = tg.appendStructFields(fields, valueOf, prefix, state)
+				return err
+			},
+		)
 		if err != nil {
 			return nil, err
 		}
-
-		addedFields++
 	}
 
-	if addedFields != typeOf.NumField() {
-		fields = fields[:addedFields]
+	if plan.hooks.after {
+		state.hooks = append(state.hooks, pendingHook{
+			value:  valueOf,
+			prefix: prefix,
+			start:  start,
+			end:    len(fields),
+		})
 	}
 
 	return fields, nil
@@ -255,6 +616,14 @@ func (tg *TagSettings) readTagContent(tag reflect.StructTag) ([]Tag, error) {
 		return nil, nil
 	}
 
+	return tg.parseTagString(tagString)
+}
+
+func (tg *TagSettings) parseTagString(tagString string) ([]Tag, error) {
+	if tg.usesQuotes(tagString) {
+		return tg.convertQuotedTags(tagString)
+	}
+
 	if tg.Separator == "" || containsEscapeCharacter(tagString, tg.escapeCharacter) {
 		tagsSplitted, err := splitWithOptionalEscapes(
 			tagString,
@@ -277,7 +646,7 @@ func (tg *TagSettings) convertAsTags(tags []string) ([]Tag, error) {
 	for k, v := range tags {
 		tag, err := newTagFromString(v, tg.Equals, tg.escapeCharacter)
 		if err != nil {
-			return nil, err
+			return nil, withTagIndex(err, k)
 		}
 
 		tagsSlice[k] = tag
@@ -286,6 +655,38 @@ func (tg *TagSettings) convertAsTags(tags []string) ([]Tag, error) {
 	return tagsSlice, nil
 }
 
+func (tg *TagSettings) convertQuotedTags(tagString string) ([]Tag, error) {
+	parts, err := splitQuoted(
+		tagString,
+		tg.Separator,
+		tg.Equals,
+		tg.quoteCharacter,
+		tg.escapeCharacter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]Tag, len(parts))
+
+	for idx, part := range parts {
+		tag, err := newTagFromQuoted(
+			part,
+			tg.Separator,
+			tg.Equals,
+			tg.quoteCharacter,
+			tg.escapeCharacter,
+		)
+		if err != nil {
+			return nil, withTagIndex(err, idx)
+		}
+
+		tags[idx] = tag
+	}
+
+	return tags, nil
+}
+
 func (tg *TagSettings) convertTagString(tagString string) ([]Tag, error) {
 	tags := make([]Tag, strings.Count(tagString, tg.Separator)+1)
 	startIndex := 0
@@ -298,7 +699,7 @@ func (tg *TagSettings) convertTagString(tagString string) ([]Tag, error) {
 			tg.escapeCharacter,
 		)
 		if err != nil {
-			return nil, err
+			return nil, withTagIndex(err, index)
 		}
 
 		tags[index] = tag
@@ -311,57 +712,161 @@ func (tg *TagSettings) convertTagString(tagString string) ([]Tag, error) {
 		tg.escapeCharacter,
 	)
 	if err != nil {
-		return nil, err
+		return nil, withTagIndex(err, len(tags)-1)
 	}
 
 	tags[len(tags)-1] = tag
 	return tags, nil
 }
 
-func (tg *TagSettings) validateTags(tags []Tag) error {
-	for _, tag := range tags {
-		key := tg.findMatchingKey(tag.Key)
+// validateTags validates every tag of structField (zero if unknown) and
+// returns all found problems. Tags using alias or deprecated key name get
+// renamed to canonical name, deprecated uses are returned as well.
+// Context validators run after all tags passed basic validation, so they
+// see resolved and converted sibling tags.
+func (tg *TagSettings) validateTags(
+	tags []Tag,
+	structField reflect.StructField,
+) ([]error, []Deprecation) {
+	var (
+		errs         []error
+		deprecations []Deprecation
+		validated    []int // Indexes of tags with context validator.
+	)
+
+	for idx := range tags {
+		tag := &tags[idx]
+
+		key, deprecated := tg.findMatchingKey(tag.Key)
 		if key == nil && !tg.disableKeyValidation {
-			return fmt.Errorf("tag '%s' does not exist", tag.Key)
+			errs = append(errs, withTagIndex(newParseError(ErrUnknownKey, tag.Key,
+				fmt.Errorf("tag '%s' does not exist", tag.Key)), idx))
+			continue
 		}
 		if key == nil {
-			return nil
+			continue
 		}
 
-		err := tag.validate(key)
+		err := tag.validate(key, tg.escapeCharacter)
+		if err == nil && structField.Type != nil {
+			err = key.checkFieldType(tag.Key, structField.Type)
+		}
 		if err != nil {
-			return err
+			errs = append(errs, withTagIndex(err, idx))
+		} else if key.ContextValidator != nil {
+			validated = append(validated, idx)
 		}
+
+		if deprecated {
+			deprecations = append(deprecations, Deprecation{
+				Tag:  tg.Name,
+				Name: tag.Key,
+				Key:  key.Name,
+			})
+		}
+
+		tag.Key = key.Name
 	}
 
-	return nil
+	for _, idx := range validated {
+		key, _ := tg.findMatchingKey(tags[idx].Key)
+
+		err := tags[idx].validateContext(key, structField, tags)
+		if err != nil {
+			errs = append(errs, withTagIndex(err, idx))
+		}
+	}
+
+	return errs, deprecations
 }
 
-func (tg *TagSettings) findMatchingKey(key string) *Key {
+// findMatchingKey returns key with passed name. Key names take precedence
+// over aliases and deprecated names, returns deprecated(true) if name is
+// deprecated name of the key.
+func (tg *TagSettings) findMatchingKey(name string) (key *Key, deprecated bool) {
 	for idx := range tg.Keys {
-		if key == tg.Keys[idx].Name {
-			return &tg.Keys[idx]
+		if name == tg.Keys[idx].Name {
+			return &tg.Keys[idx], false
 		}
 	}
 
-	return nil
+	for idx := range tg.Keys {
+		if ok, deprecated := tg.Keys[idx].matchName(name); ok {
+			return &tg.Keys[idx], deprecated
+		}
+	}
+
+	return nil, false
 }
 
-func (tg *TagSettings) hasRequiredKeys(field Field) error {
-	if len(tg.keysRequired) == 0 {
-		return nil
-	}
+// checkDuplicates applies duplicate policies to validated tags. Returns
+// error for every rejected duplicate, tags are returned without dropped
+// duplicates.
+func (tg *TagSettings) checkDuplicates(tags []Tag) ([]Tag, []error) {
+	var (
+		errs    []error
+		dropped map[int]bool
+	)
 
-	for _, v := range tg.keysRequired {
-		if field.HasKey(v) {
+	for idx := range tags {
+		first := tagIndex(tags, tags[idx].Key)
+		if first == idx {
 			continue
 		}
 
-		return fmt.Errorf("%s: key '%s' is required but not found",
-			field.Name, v)
+		switch tg.duplicatePolicy(tags[idx].Key) {
+		case DuplicatesReject:
+			errs = append(errs, withTagIndex(newParseError(ErrDuplicateKey, tags[idx].Key,
+				fmt.Errorf("tag '%s' is duplicated", tags[idx].Key)), idx))
+		case DuplicatesKeepLast:
+			if dropped == nil {
+				dropped = make(map[int]bool)
+			}
+
+			for prev := first; prev < idx; prev++ {
+				if tags[prev].Key == tags[idx].Key {
+					dropped[prev] = true
+				}
+			}
+		}
 	}
 
-	return nil
+	if len(dropped) == 0 {
+		return tags, errs
+	}
+
+	kept := make([]Tag, 0, len(tags)-len(dropped))
+	for idx, tag := range tags {
+		if !dropped[idx] {
+			kept = append(kept, tag)
+		}
+	}
+
+	return kept, errs
+}
+
+// duplicatePolicy returns duplicate policy of key.
+func (tg *TagSettings) duplicatePolicy(name string) DuplicatePolicy {
+	key, _ := tg.findMatchingKey(name)
+	if key != nil && key.Duplicates != DuplicatesDefault {
+		return key.Duplicates
+	}
+
+	return tg.duplicates
+}
+
+// missingRequiredKeys returns required keys not found in tags.
+func (tg *TagSettings) missingRequiredKeys(tags []Tag) []string {
+	field := Field{Tags: tags}
+	var missing []string
+
+	for _, v := range tg.keysRequired {
+		if !field.HasKey(v) {
+			missing = append(missing, v)
+		}
+	}
+
+	return missing
 }
 
 func (tg *TagSettings) requiredKeys() []string {
@@ -0,0 +1,329 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gaigals/gotags"
+)
+
+var (
+	numberKinds = []reflect.Kind{
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+	}
+	sizeKinds  = append([]reflect.Kind{reflect.String, reflect.Slice, reflect.Array, reflect.Map}, numberKinds...)
+	equalKinds = append([]reflect.Kind{reflect.String, reflect.Bool}, numberKinds...)
+
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+type rule struct {
+	key   gotags.Key
+	check Check
+}
+
+func standardRules() []rule {
+	return []rule{
+		{gotags.NewKey("required", true, false, nil), checkRequired},
+		{equalKey("eq"), checkEqual(true)},
+		{equalKey("ne"), checkEqual(false)},
+		{sizeKey("gt"), checkSize("greater than", func(c int) bool { return c > 0 })},
+		{sizeKey("gte"), checkSize("at least", func(c int) bool { return c >= 0 })},
+		{sizeKey("lt"), checkSize("less than", func(c int) bool { return c < 0 })},
+		{sizeKey("lte"), checkSize("at most", func(c int) bool { return c <= 0 })},
+		{sizeKey("len"), checkSize("equal to", func(c int) bool { return c == 0 })},
+		{sizeKey("min"), checkSize("at least", func(c int) bool { return c >= 0 })},
+		{sizeKey("max"), checkSize("at most", func(c int) bool { return c <= 0 })},
+		{
+			gotags.NewKey("oneof", false, false, nil).
+				WithStringList(" ").
+				WithKinds(equalKinds...).
+				WithContextValidator(checkArguments(compareValue)),
+			checkOneOf,
+		},
+		{
+			gotags.NewKey("regex", false, false, nil).
+				WithType(gotags.ValueRegexp).
+				WithKinds(reflect.String),
+			checkRegex,
+		},
+		{gotags.NewKey("email", true, false, nil).WithKinds(reflect.String), checkEmail},
+		{gotags.NewKey("url", true, false, nil).WithKinds(reflect.String), checkURL},
+	}
+}
+
+func equalKey(name string) gotags.Key {
+	return gotags.NewKey(name, false, false, nil).
+		WithKinds(equalKinds...).
+		WithContextValidator(checkArgument(compareValue))
+}
+
+func sizeKey(name string) gotags.Key {
+	return gotags.NewKey(name, false, false, nil).
+		WithKinds(sizeKinds...).
+		WithContextValidator(checkArgument(compareSize))
+}
+
+// checkArgument returns context validator which checks that rule argument
+// can be compared with field value.
+func checkArgument(
+	compare func(value reflect.Value, arg string) (int, error),
+) gotags.ContextValidator {
+	return func(ctx gotags.ValidationContext) error {
+		value, ok := zeroValue(ctx.Field)
+		if !ok {
+			return nil
+		}
+
+		_, err := compare(value, ctx.Tag.Value)
+		return err
+	}
+}
+
+// checkArguments is checkArgument for rules which take list of arguments,
+// every list item must be comparable with field value.
+func checkArguments(
+	compare func(value reflect.Value, arg string) (int, error),
+) gotags.ContextValidator {
+	return func(ctx gotags.ValidationContext) error {
+		value, ok := zeroValue(ctx.Field)
+		if !ok {
+			return nil
+		}
+
+		for _, item := range ctx.Tag.Strings() {
+			if _, err := compare(value, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// zeroValue returns zero value of field type with pointers removed, false if
+// field type is unknown.
+func zeroValue(field reflect.StructField) (reflect.Value, bool) {
+	if field.Type == nil {
+		return reflect.Value{}, false
+	}
+
+	fieldType := field.Type
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return reflect.Zero(fieldType), true
+}
+
+func checkRequired(value reflect.Value, _ gotags.Tag) error {
+	if value.IsZero() {
+		return errors.New("is required")
+	}
+
+	return nil
+}
+
+func checkEqual(equal bool) Check {
+	return func(value reflect.Value, tag gotags.Tag) error {
+		value, ok := Indirect(value)
+		if !ok {
+			return nil
+		}
+
+		compared, err := compareValue(value, tag.Value)
+		if err != nil {
+			return err
+		}
+
+		if equal && compared != 0 {
+			return fmt.Errorf("must be equal to %s", tag.Value)
+		}
+		if !equal && compared == 0 {
+			return fmt.Errorf("must not be equal to %s", tag.Value)
+		}
+
+		return nil
+	}
+}
+
+func checkSize(description string, passes func(compared int) bool) Check {
+	return func(value reflect.Value, tag gotags.Tag) error {
+		value, ok := Indirect(value)
+		if !ok {
+			return nil
+		}
+
+		compared, err := compareSize(value, tag.Value)
+		if err != nil {
+			return err
+		}
+		if passes(compared) {
+			return nil
+		}
+
+		if isNumber(value.Kind()) {
+			return fmt.Errorf("must be %s %s", description, tag.Value)
+		}
+
+		return fmt.Errorf("length must be %s %s", description, tag.Value)
+	}
+}
+
+func checkOneOf(value reflect.Value, tag gotags.Tag) error {
+	value, ok := Indirect(value)
+	if !ok {
+		return nil
+	}
+
+	for _, allowed := range tag.Strings() {
+		compared, err := compareValue(value, allowed)
+		if err == nil && compared == 0 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("must be one of %q", tag.Strings())
+}
+
+func checkRegex(value reflect.Value, tag gotags.Tag) error {
+	value, ok := Indirect(value)
+	if !ok {
+		return nil
+	}
+
+	if !tag.Regexp().MatchString(value.String()) {
+		return fmt.Errorf("must match '%s'", tag.Value)
+	}
+
+	return nil
+}
+
+func checkEmail(value reflect.Value, _ gotags.Tag) error {
+	value, ok := Indirect(value)
+	if !ok {
+		return nil
+	}
+
+	address, err := mail.ParseAddress(value.String())
+	if err != nil || address.Address != value.String() {
+		return errors.New("must be valid email address")
+	}
+
+	return nil
+}
+
+func checkURL(value reflect.Value, _ gotags.Tag) error {
+	value, ok := Indirect(value)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := url.Parse(value.String())
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return errors.New("must be valid URL")
+	}
+
+	return nil
+}
+
+// compareValue compares value with arg, strings are compared as is.
+// Returns -1, 0 or 1 like strings.Compare, 0 means equal.
+func compareValue(value reflect.Value, arg string) (int, error) {
+	switch value.Kind() {
+	case reflect.String:
+		if value.String() == arg {
+			return 0, nil
+		}
+		return 1, nil
+	case reflect.Bool:
+		converted, err := strconv.ParseBool(arg)
+		if err != nil {
+			return 0, fmt.Errorf("requires boolean value, got '%s'", arg)
+		}
+		if value.Bool() == converted {
+			return 0, nil
+		}
+		return 1, nil
+	default:
+		return compareSize(value, arg)
+	}
+}
+
+// compareSize compares numbers by value, strings and containers by length.
+// Returns -1, 0 or 1 like strings.Compare.
+func compareSize(value reflect.Value, arg string) (int, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return compareInt(int64(utf8.RuneCountInString(value.String())), arg, strconv.IntSize)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return compareInt(int64(value.Len()), arg, strconv.IntSize)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Type() == durationType {
+			return compareDuration(time.Duration(value.Int()), arg)
+		}
+		return compareInt(value.Int(), arg, value.Type().Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		converted, err := strconv.ParseUint(arg, 0, value.Type().Bits())
+		if err != nil {
+			return 0, fmt.Errorf("requires unsigned integer value, got '%s'", arg)
+		}
+		return compareOrdered(value.Uint(), converted), nil
+	case reflect.Float32, reflect.Float64:
+		converted, err := strconv.ParseFloat(arg, value.Type().Bits())
+		if err != nil {
+			return 0, fmt.Errorf("requires float value, got '%s'", arg)
+		}
+		return compareOrdered(value.Float(), converted), nil
+	default:
+		return 0, fmt.Errorf("cannot compare value of type %s", value.Type())
+	}
+}
+
+// compareInt compares size with arg, arg must fit integer of bitSize.
+func compareInt(size int64, arg string, bitSize int) (int, error) {
+	converted, err := strconv.ParseInt(arg, 0, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("requires integer value, got '%s'", arg)
+	}
+
+	return compareOrdered(size, converted), nil
+}
+
+func compareDuration(duration time.Duration, arg string) (int, error) {
+	converted, err := time.ParseDuration(arg)
+	if err != nil {
+		return 0, fmt.Errorf("requires duration value, got '%s'", arg)
+	}
+
+	return compareOrdered(duration, converted), nil
+}
+
+func compareOrdered[T int64 | uint64 | float64 | time.Duration](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNumber(kind reflect.Kind) bool {
+	for _, v := range numberKinds {
+		if v == kind {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,163 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/gaigals/gotags"
+)
+
+type testUser struct {
+	Name    string        `validate:"required;min:2;max:5"`
+	Age     uint          `validate:"gt:10;lt:130"`
+	Score   *float64      `validate:"gte:0.5;lte:1"`
+	Role    string        `validate:"oneof:admin user"`
+	Code    string        `validate:"regex:^[A-Z]+$;ne:XX"`
+	Email   string        `validate:"email"`
+	Site    string        `validate:"url"`
+	Tags    []string      `validate:"len:2"`
+	Timeout time.Duration `validate:"gt:1s"`
+	Active  bool          `validate:"eq:true"`
+	Note    string
+}
+
+func newValidUser() *testUser {
+	score := 0.7
+
+	return &testUser{
+		Name:    "John",
+		Age:     30,
+		Score:   &score,
+		Role:    "admin",
+		Code:    "AB",
+		Email:   "john@example.com",
+		Site:    "https://example.com/john",
+		Tags:    []string{"a", "b"},
+		Timeout: 2 * time.Second,
+		Active:  true,
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	validator := New(gotags.NewSettings("validate"))
+
+	t.Run("Valid struct", func(t *testing.T) {
+		err := validator.Validate(newValidUser())
+		testza.AssertNoError(t, err, "unexpected error")
+	})
+
+	t.Run("Nil pointer skips rules", func(t *testing.T) {
+		user := newValidUser()
+		user.Score = nil
+
+		err := validator.Validate(user)
+		testza.AssertNoError(t, err, "unexpected error")
+	})
+
+	t.Run("Every violation is returned", func(t *testing.T) {
+		score := 2.0
+		user := &testUser{
+			Name:    "J",
+			Age:     5,
+			Score:   &score,
+			Role:    "guest",
+			Code:    "XX",
+			Email:   "John <john@example.com>",
+			Site:    "example.com",
+			Tags:    []string{"a"},
+			Timeout: time.Second,
+		}
+
+		err := validator.Validate(user)
+
+		var errs Errors
+		testza.AssertTrue(t, errors.As(err, &errs), "expected Errors")
+		testza.AssertEqual(t, err.Error(), `field 'Name': length must be at least 2
+field 'Age': must be greater than 10
+field 'Score': must be at most 1
+field 'Role': must be one of ["admin" "user"]
+field 'Code': must not be equal to XX
+field 'Email': must be valid email address
+field 'Site': must be valid URL
+field 'Tags': length must be equal to 2
+field 'Timeout': must be greater than 1s
+field 'Active': must be equal to true`, "unexpected errors")
+
+		testza.AssertEqual(t, errs[1].Field, "Age", "unexpected field")
+		testza.AssertEqual(t, errs[1].Key, "gt", "unexpected key")
+		testza.AssertEqual(t, errs[1].Value, "10", "unexpected value")
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		user := newValidUser()
+		user.Name = ""
+
+		err := validator.Validate(user)
+		testza.AssertEqual(t, err.Error(),
+			"field 'Name': is required\nfield 'Name': length must be at least 2",
+			"unexpected errors")
+	})
+}
+
+func Test_RuleArguments(t *testing.T) {
+	validator := New(gotags.NewSettings("validate"))
+
+	t.Run("Argument must fit field type", func(t *testing.T) {
+		err := validator.Validate(&struct {
+			Age int `validate:"gt:abc"`
+		}{})
+		testza.AssertErrorIs(t, err, gotags.ErrInvalidValue, "expected invalid value")
+		testza.AssertEqual(t, err.Error(), "field 'Age': requires integer value, got 'abc'",
+			"unexpected error")
+	})
+
+	t.Run("Argument must fit field size", func(t *testing.T) {
+		err := validator.Validate(&struct {
+			Small int8 `validate:"lt:300"`
+		}{})
+		testza.AssertErrorIs(t, err, gotags.ErrInvalidValue, "expected invalid value")
+		testza.AssertEqual(t, err.Error(), "field 'Small': requires integer value, got '300'",
+			"unexpected error")
+	})
+
+	t.Run("Every oneof item must fit field type", func(t *testing.T) {
+		err := validator.Validate(&struct {
+			Level int `validate:"oneof:1 2 high"`
+		}{})
+		testza.AssertErrorIs(t, err, gotags.ErrInvalidValue, "expected invalid value")
+		testza.AssertEqual(t, err.Error(), "field 'Level': requires integer value, got 'high'",
+			"unexpected error")
+
+		err = validator.Validate(&struct {
+			Level int `validate:"oneof:1 2 3"`
+		}{Level: 2})
+		testza.AssertNoError(t, err, "unexpected error")
+	})
+
+	t.Run("Rule must fit field kind", func(t *testing.T) {
+		err := validator.Validate(&struct {
+			Age int `validate:"regex:^a"`
+		}{})
+		testza.AssertErrorIs(t, err, gotags.ErrFieldTypeMismatch, "expected type error")
+	})
+}
+
+func Test_AddRule(t *testing.T) {
+	validator := New(gotags.NewSettings("validate")).AddRule(
+		gotags.NewKey("even", true, false, nil),
+		func(value reflect.Value, _ gotags.Tag) error {
+			if value.Int()%2 != 0 {
+				return errors.New("must be even")
+			}
+			return nil
+		},
+	)
+
+	err := validator.Validate(&struct {
+		Count int `validate:"even"`
+	}{Count: 3})
+	testza.AssertEqual(t, err.Error(), "field 'Count': must be even", "unexpected error")
+}
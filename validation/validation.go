@@ -0,0 +1,161 @@
+// Package validation validates struct field values with rules declared in
+// gotags tags, like `validate:"required;gt:10;lt:130"`.
+//
+//	settings := gotags.NewSettings("validate")
+//	validator := validation.New(settings)
+//
+//	err := validator.Validate(&user)
+//
+//	var errs validation.Errors
+//	if errors.As(err, &errs) {
+//		for _, err := range errs {
+//			fmt.Println(err) // field 'Age': must be greater than 10
+//		}
+//	}
+//
+// Rule arguments are checked against field types while parsing, so
+// `gt:abc` on int field or `regex` on int field fail in ParseStruct.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gaigals/gotags"
+)
+
+// Check validates field value against tag. Value is the field value as is,
+// use Indirect to dereference pointers.
+type Check func(value reflect.Value, tag gotags.Tag) error
+
+// Validator runs rules of TagSettings keys against parsed field values.
+type Validator struct {
+	settings *gotags.TagSettings
+	checks   map[string]Check
+}
+
+// FieldError is single failed rule.
+type FieldError struct {
+	Field string // Full field path, like "Server.Port".
+	Key   string // Rule key, like "gt".
+	Value string // Rule argument, like "10".
+	Err   error
+}
+
+// Errors holds every failed rule of validated struct.
+type Errors []*FieldError
+
+// New registers standard rule keys on settings and returns Validator using
+// them. Standard rules are required, eq, ne, gt, gte, lt, lte, len, min,
+// max, oneof, regex, email and url.
+// Note: settings must not already have keys with the same names.
+func New(settings *gotags.TagSettings) *Validator {
+	validator := &Validator{
+		settings: settings,
+		checks:   make(map[string]Check),
+	}
+
+	for _, rule := range standardRules() {
+		validator.AddRule(rule.key, rule.check)
+	}
+
+	return validator
+}
+
+// AddRule registers key on settings and check to run for fields tagged with
+// that key.
+func (validator *Validator) AddRule(key gotags.Key, check Check) *Validator {
+	validator.settings.AddKey(key)
+	validator.checks[key.Name] = check
+	return validator
+}
+
+// Settings returns TagSettings rules are registered on.
+func (validator *Validator) Settings() *gotags.TagSettings {
+	return validator.settings
+}
+
+// Validate parses passed struct and validates its field values.
+// Tag problems are returned as is, failed rules are returned as Errors.
+func (validator *Validator) Validate(data any) error {
+	fields, err := validator.settings.ParseStruct(data)
+	if err != nil {
+		return err
+	}
+
+	return validator.ValidateFields(fields)
+}
+
+// ValidateFields validates values of already parsed fields.
+// Failed rules are returned as Errors, nil if every rule passed.
+func (validator *Validator) ValidateFields(fields []gotags.Field) error {
+	var errs Errors
+
+	for _, field := range fields {
+		for _, tag := range field.Tags {
+			check, ok := validator.checks[tag.Key]
+			if !ok {
+				continue
+			}
+
+			err := check(field.Value, tag)
+			if err == nil {
+				continue
+			}
+
+			errs = append(errs, &FieldError{
+				Field: field.Path,
+				Key:   tag.Key,
+				Value: tag.Value,
+				Err:   err,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("field '%s': %v", err.Field, err.Err)
+}
+
+func (err *FieldError) Unwrap() error {
+	return err.Err
+}
+
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+	for idx, err := range errs {
+		messages[idx] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns every field error, so errors.As and errors.Is see them.
+func (errs Errors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for idx, err := range errs {
+		unwrapped[idx] = err
+	}
+
+	return unwrapped
+}
+
+// Indirect dereferences pointers, returns ok(false) if value is nil pointer.
+func Indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	return value, true
+}
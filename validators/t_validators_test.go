@@ -0,0 +1,114 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/gaigals/gotags"
+)
+
+func Test_Validators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator gotags.Validator
+		valid     []string
+		invalid   map[string]string // Value => error message.
+	}{
+		{
+			name:      "Int",
+			validator: Int(),
+			valid:     []string{"10", "-3", "0x1f"},
+			invalid:   map[string]string{"abc": "requires integer value, got 'abc'"},
+		},
+		{
+			name:      "IntRange",
+			validator: IntRange(1, 10),
+			valid:     []string{"1", "10"},
+			invalid: map[string]string{
+				"11":  "value '11' must be in range [1, 10]",
+				"1.5": "requires integer value, got '1.5'",
+			},
+		},
+		{
+			name:      "FloatRange",
+			validator: FloatRange(0, 1),
+			valid:     []string{"0", "0.5", "1"},
+			invalid:   map[string]string{"1.5": "value '1.5' must be in range [0, 1]"},
+		},
+		{
+			name:      "Duration",
+			validator: Duration(),
+			valid:     []string{"1s", "2h45m"},
+			invalid:   map[string]string{"10": "requires duration value, got '10'"},
+		},
+		{
+			name:      "Regexp",
+			validator: Regexp(),
+			valid:     []string{"^[a-z]+$"},
+			invalid: map[string]string{
+				"[": "requires regexp value: error parsing regexp: missing closing ]: `[`",
+			},
+		},
+		{
+			name:      "OneOf",
+			validator: OneOf("fast", "slow"),
+			valid:     []string{"fast", "slow"},
+			invalid:   map[string]string{"medium": `value 'medium' must be one of ["fast" "slow"]`},
+		},
+		{
+			name:      "List",
+			validator: List(",", IntRange(1, 5)),
+			valid:     []string{"1", "1,2,5"},
+			invalid:   map[string]string{"1,9": "list item 1: value '9' must be in range [1, 5]"},
+		},
+		{
+			name:      "ListWithEscape",
+			validator: ListWithEscape(",", '\\', OneOf("a,b", "c")),
+			valid:     []string{"c", `a\,b,c`},
+			invalid:   map[string]string{"a,b": `list item 0: value 'a' must be one of ["a,b" "c"]`},
+		},
+		{
+			name:      "All",
+			validator: All(Int(), Not(OneOf("0"))),
+			valid:     []string{"1"},
+			invalid: map[string]string{
+				"0": "value '0' is not allowed",
+				"a": "requires integer value, got 'a'",
+			},
+		},
+		{
+			name:      "Any",
+			validator: Any(Duration(), Match(`^\d+$`)),
+			valid:     []string{"1s", "10"},
+			invalid: map[string]string{
+				"x": "requires duration value, got 'x'\nvalue 'x' must match '^\\d+$'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, value := range test.valid {
+				testza.AssertNoError(t, test.validator(value), value)
+			}
+
+			for value, message := range test.invalid {
+				err := test.validator(value)
+				testza.AssertNotNil(t, err, value)
+				testza.AssertEqual(t, err.Error(), message, value)
+			}
+		})
+	}
+}
+
+func Test_ValidatorsWithKey(t *testing.T) {
+	settings := gotags.NewSettings("validator").
+		AddKeys(gotags.NewKey("port", false, false, IntRange(1, 65535)))
+
+	_, err := settings.ParseStruct(&struct {
+		Port int `validator:"port:70000"`
+	}{})
+	testza.AssertErrorIs(t, err, gotags.ErrInvalidValue, "expected invalid value")
+	testza.AssertEqual(t, err.Error(), "field 'Port': value '70000' must be in range [1, 65535]",
+		"unexpected error")
+}
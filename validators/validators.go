@@ -0,0 +1,235 @@
+// Package validators provides ready-made, composable gotags.Validator
+// constructors for common tag values.
+//
+//	gotags.NewKey("gt", false, false, validators.Int())
+//	gotags.NewKey("port", false, false, validators.IntRange(1, 65535))
+//	gotags.NewKey("oneof", false, false, validators.List(" ", validators.NotEmpty()))
+//
+// Errors are wrapped by gotags into *gotags.ParseError of
+// gotags.ErrInvalidValue kind, naming the field.
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gaigals/gotags"
+)
+
+// Int validates that value is an integer (strconv.ParseInt with base 0).
+func Int() gotags.Validator {
+	return func(value string) error {
+		_, err := parseInt(value)
+		return err
+	}
+}
+
+// IntRange validates that value is an integer within [min, max].
+func IntRange(min, max int) gotags.Validator {
+	return func(value string) error {
+		converted, err := parseInt(value)
+		if err != nil {
+			return err
+		}
+
+		if converted < min || converted > max {
+			return fmt.Errorf("value '%s' must be in range [%d, %d]", value, min, max)
+		}
+
+		return nil
+	}
+}
+
+// Float validates that value is a floating point number.
+func Float() gotags.Validator {
+	return func(value string) error {
+		_, err := parseFloat(value)
+		return err
+	}
+}
+
+// FloatRange validates that value is a number within [min, max].
+func FloatRange(min, max float64) gotags.Validator {
+	return func(value string) error {
+		converted, err := parseFloat(value)
+		if err != nil {
+			return err
+		}
+
+		if converted < min || converted > max {
+			return fmt.Errorf("value '%s' must be in range [%g, %g]", value, min, max)
+		}
+
+		return nil
+	}
+}
+
+// Bool validates that value is a boolean (strconv.ParseBool).
+func Bool() gotags.Validator {
+	return func(value string) error {
+		_, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("requires boolean value, got '%s'", value)
+		}
+
+		return nil
+	}
+}
+
+// Duration validates that value is a time.Duration (time.ParseDuration).
+func Duration() gotags.Validator {
+	return func(value string) error {
+		_, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("requires duration value, got '%s'", value)
+		}
+
+		return nil
+	}
+}
+
+// Regexp validates that value is a valid regular expression.
+func Regexp() gotags.Validator {
+	return func(value string) error {
+		_, err := regexp.Compile(value)
+		if err != nil {
+			return fmt.Errorf("requires regexp value: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// Match validates that value matches regular expression pattern.
+// Panics if pattern is not valid, like regexp.MustCompile.
+func Match(pattern string) gotags.Validator {
+	compiled := regexp.MustCompile(pattern)
+
+	return func(value string) error {
+		if !compiled.MatchString(value) {
+			return fmt.Errorf("value '%s' must match '%s'", value, pattern)
+		}
+
+		return nil
+	}
+}
+
+// OneOf validates that value is one of passed values.
+func OneOf(values ...string) gotags.Validator {
+	return func(value string) error {
+		for _, v := range values {
+			if v == value {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("value '%s' must be one of %q", value, values)
+	}
+}
+
+// NotEmpty validates that value is not empty.
+func NotEmpty() gotags.Validator {
+	return func(value string) error {
+		if value == "" {
+			return errors.New("value must not be empty")
+		}
+
+		return nil
+	}
+}
+
+// List splits value by separator and validates every item with validator.
+// Escaped separators are not handled, see ListWithEscape.
+func List(separator string, validator gotags.Validator) gotags.Validator {
+	return ListWithEscape(separator, 0, validator)
+}
+
+// ListWithEscape is List which does not split by separators escaped with
+// escapeCharacter, like gotags.SplitWithEscape. Use the escape character of
+// TagSettings to split the same way gotags.Key.WithStringList does.
+func ListWithEscape(
+	separator string,
+	escapeCharacter byte,
+	validator gotags.Validator,
+) gotags.Validator {
+	return func(value string) error {
+		items, err := gotags.SplitWithEscape(value, separator, escapeCharacter)
+		if err != nil {
+			return err
+		}
+
+		for idx, item := range items {
+			err := validator(item)
+			if err != nil {
+				return fmt.Errorf("list item %d: %w", idx, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// All validates that value passes every validator, first error is returned.
+func All(validators ...gotags.Validator) gotags.Validator {
+	return func(value string) error {
+		for _, validator := range validators {
+			err := validator(value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Any validates that value passes at least one validator, errors of all
+// validators are returned if none passes.
+func Any(validators ...gotags.Validator) gotags.Validator {
+	return func(value string) error {
+		errs := make([]error, 0, len(validators))
+
+		for _, validator := range validators {
+			err := validator(value)
+			if err == nil {
+				return nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// Not validates that value does not pass validator.
+func Not(validator gotags.Validator) gotags.Validator {
+	return func(value string) error {
+		if validator(value) == nil {
+			return fmt.Errorf("value '%s' is not allowed", value)
+		}
+
+		return nil
+	}
+}
+
+func parseInt(value string) (int, error) {
+	converted, err := strconv.ParseInt(value, 0, strconv.IntSize)
+	if err != nil {
+		return 0, fmt.Errorf("requires integer value, got '%s'", value)
+	}
+
+	return int(converted), nil
+}
+
+func parseFloat(value string) (float64, error) {
+	converted, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("requires float value, got '%s'", value)
+	}
+
+	return converted, nil
+}
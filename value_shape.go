@@ -0,0 +1,116 @@
+package gotags
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// callValue is converted value of ValueCall key.
+type callValue struct {
+	name string
+	args []string
+}
+
+// listSeparator returns separator of list, map and call values.
+func (key *Key) listSeparator() string {
+	if key.ListSeparator == "" {
+		return defaultListSeparator
+	}
+
+	return key.ListSeparator
+}
+
+// mapEquals returns separator of map entry key and value.
+func (key *Key) mapEquals() string {
+	if key.MapEquals == "" {
+		return defaultMapEquals
+	}
+
+	return key.MapEquals
+}
+
+// convertMap splits value into entries and every entry into key and value.
+// Escapes are unescaped layer by layer, like tags and their values are.
+func (key *Key) convertMap(value string, escapeCharacter byte) (any, error) {
+	entries, err := SplitWithEscape(value, key.listSeparator(), escapeCharacter)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		parts, err := SplitFirstWithEscape(entry, key.mapEquals(), escapeCharacter)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, key.valueTypeError(value)
+		}
+
+		if _, ok := converted[parts[0]]; ok {
+			return nil, fmt.Errorf("tag '%s' map key '%s' is duplicated",
+				key.Name, parts[0])
+		}
+
+		converted[parts[0]] = parts[1]
+	}
+
+	return converted, nil
+}
+
+// convertCall splits value like `oneof(a,b)` into name and arguments.
+func (key *Key) convertCall(value string, escapeCharacter byte) (any, error) {
+	open := indexUnescaped(value, "(", escapeCharacter)
+	if open <= 0 || !strings.HasSuffix(value, ")") {
+		return nil, key.valueTypeError(value)
+	}
+
+	call := callValue{name: value[:open]}
+
+	args := value[open+1 : len(value)-1]
+	if args == "" {
+		return call, nil
+	}
+
+	var err error
+	call.args, err = SplitWithEscape(args, key.listSeparator(), escapeCharacter)
+	if err != nil {
+		return nil, err
+	}
+
+	return call, nil
+}
+
+// Values returns copy of elements of ValueStringList key or arguments of
+// ValueCall key. Returns nil for other keys.
+func (tag Tag) Values() []string {
+	switch converted := tag.converted().(type) {
+	case []string:
+		return slices.Clone(converted)
+	case callValue:
+		return slices.Clone(converted.args)
+	default:
+		return nil
+	}
+}
+
+// Map returns copy of entries of ValueMap key.
+// Returns nil if key is not declared as map.
+func (tag Tag) Map() map[string]string {
+	converted, _ := tag.converted().(map[string]string)
+	return maps.Clone(converted)
+}
+
+// Call returns name and arguments of ValueCall key, like "oneof" and
+// ["a", "b"] of `oneof(a,b)`. Whole value is returned as name if key is not
+// declared as call.
+func (tag Tag) Call() (name string, args []string) {
+	if converted, ok := tag.converted().(callValue); ok {
+		return converted.name, slices.Clone(converted.args)
+	}
+
+	return tag.Value, nil
+}
@@ -0,0 +1,181 @@
+package gotags
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// String returns value type name used in error messages.
+func (valueType ValueType) String() string {
+	switch valueType {
+	case ValueString:
+		return "string"
+	case ValueInt:
+		return "integer"
+	case ValueFloat:
+		return "float"
+	case ValueBool:
+		return "boolean"
+	case ValueDuration:
+		return "duration"
+	case ValueRegexp:
+		return "regexp"
+	case ValueEnum:
+		return "enum"
+	case ValueStringList:
+		return "string list"
+	case ValueFieldRef:
+		return "field reference"
+	case ValueMap:
+		return "map"
+	case ValueCall:
+		return "call"
+	case ValueTags:
+		return "tags"
+	default:
+		return fmt.Sprintf("ValueType(%d)", int(valueType))
+	}
+}
+
+// convertValue converts tag value to key value type.
+// Returns nil value for ValueString keys.
+func (key *Key) convertValue(value string, escapeCharacter byte) (any, error) {
+	switch key.Type {
+	case ValueInt:
+		converted, err := strconv.ParseInt(value, 0, strconv.IntSize)
+		if err != nil {
+			return nil, key.valueTypeError(value)
+		}
+		return int(converted), nil
+	case ValueFloat:
+		converted, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, key.valueTypeError(value)
+		}
+		return converted, nil
+	case ValueBool:
+		converted, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, key.valueTypeError(value)
+		}
+		return converted, nil
+	case ValueDuration:
+		converted, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, key.valueTypeError(value)
+		}
+		return converted, nil
+	case ValueRegexp:
+		converted, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("tag '%s' requires regexp value: %w",
+				key.Name, err)
+		}
+		return converted, nil
+	case ValueEnum:
+		for _, v := range key.Enum {
+			if v == value {
+				return nil, nil
+			}
+		}
+		return nil, fmt.Errorf("tag '%s' value '%s' must be one of %q",
+			key.Name, value, key.Enum)
+	case ValueStringList:
+		return SplitWithEscape(value, key.listSeparator(), escapeCharacter)
+	case ValueFieldRef:
+		return key.convertFieldRef(value, escapeCharacter)
+	case ValueMap:
+		return key.convertMap(value, escapeCharacter)
+	case ValueCall:
+		return key.convertCall(value, escapeCharacter)
+	case ValueTags:
+		return key.convertTags(value)
+	default:
+		return nil, nil
+	}
+}
+
+func (key *Key) valueTypeError(value string) error {
+	return fmt.Errorf("tag '%s' requires %s value, got '%s'",
+		key.Name, key.Type, value)
+}
+
+// Int returns value of ValueInt key. Values of not typed keys are converted
+// on the fly, 0 is returned if value is not an integer.
+func (tag Tag) Int() int {
+	if converted, ok := tag.converted().(int); ok {
+		return converted
+	}
+
+	converted, _ := strconv.ParseInt(tag.Value, 0, strconv.IntSize)
+	return int(converted)
+}
+
+// Float returns value of ValueFloat key. Values of not typed keys are
+// converted on the fly, 0 is returned if value is not a number.
+func (tag Tag) Float() float64 {
+	if converted, ok := tag.converted().(float64); ok {
+		return converted
+	}
+
+	converted, _ := strconv.ParseFloat(tag.Value, 64)
+	return converted
+}
+
+// Bool returns value of ValueBool key. Values of not typed keys are
+// converted on the fly, false is returned if value is not a boolean.
+func (tag Tag) Bool() bool {
+	if converted, ok := tag.converted().(bool); ok {
+		return converted
+	}
+
+	converted, _ := strconv.ParseBool(tag.Value)
+	return converted
+}
+
+// Duration returns value of ValueDuration key. Values of not typed keys are
+// converted on the fly, 0 is returned if value is not a duration.
+func (tag Tag) Duration() time.Duration {
+	if converted, ok := tag.converted().(time.Duration); ok {
+		return converted
+	}
+
+	converted, _ := time.ParseDuration(tag.Value)
+	return converted
+}
+
+// Regexp returns compiled value of ValueRegexp key. Values of not typed keys
+// are compiled on the fly, nil is returned if value is not valid regexp.
+func (tag Tag) Regexp() *regexp.Regexp {
+	if converted, ok := tag.converted().(*regexp.Regexp); ok {
+		return converted
+	}
+
+	converted, err := regexp.Compile(tag.Value)
+	if err != nil {
+		return nil
+	}
+
+	return converted
+}
+
+// Strings returns copy of values of ValueStringList key.
+// Returns nil if key is not declared as list.
+func (tag Tag) Strings() []string {
+	converted, _ := tag.converted().([]string)
+	return slices.Clone(converted)
+}
+
+// FieldRef returns referenced field path and the rest of ValueFieldRef key
+// value, like "Type" and "admin" of `requiredIf:Type=admin`.
+// Whole value is returned as path if key is not declared as reference.
+func (tag Tag) FieldRef() (path, rest string) {
+	if converted, ok := tag.converted().(fieldRef); ok {
+		return converted.path, converted.rest
+	}
+
+	return tag.Value, ""
+}